@@ -1,31 +1,117 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // DEFAULT_TIMEZONE in images without zoneinfo
 
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/grpc"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/imageproc"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/ocr"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/ollama"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/openai"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/rules"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/admission"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/env"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/grpcserver"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/healthcheck"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/ports"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/usecase"
+	aiwpbv3 "github.com/cp25sy5-modjot/ai-wrapper-service/proto/gen/ai/v3"
+	aiwpb "github.com/cp25sy5-modjot/proto/gen/ai/v2"
+	grpcgo "google.golang.org/grpc"
 )
 
 func main() {
-	addr := env("GRPC_ADDR", ":50051")
+	addr := env.String("GRPC_ADDR", ":50051")
 
 	// Adapters (infrastructure)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	ocrCli := ocr.NewTyphoonOCR()
-	ollamaAdapter := ollama.NewOllamaAdapter()
+	llmName, llmAdapter := newLLMBackend(ctx, env.String("LLM_BACKEND", "ollama"))
 
-	// Application service (use cases)
-	aiSvc := usecase.NewAIService(ocrCli, ollamaAdapter)
+	limits := usecase.UploadLimits{
+		MaxBytes:  env.Int("MAX_UPLOAD_BYTES", 20<<20),
+		MaxPixels: env.Int("MAX_IMAGE_PIXELS", 50_000_000),
+	}
 
 	// gRPC server (interface adapter)
-	s := grpcserver.New(addr)
+	var srvOpts []grpcgo.ServerOption
+	if limits.MaxBytes > 0 {
+		// room for the other request fields on top of image_data
+		srvOpts = append(srvOpts, grpcgo.MaxRecvMsgSize(limits.MaxBytes+64<<10))
+	}
+	s := grpcserver.New(addr, srvOpts...)
+
+	// Dependency health drives both grpc_health_v1 and the Check RPC
+	monitor := healthcheck.NewMonitor(
+		map[string]ports.HealthPort{"ocr": ocrCli, llmName: llmAdapter},
+		env.Duration("HEALTH_CHECK_INTERVAL", 15*time.Second),
+		env.Duration("HEALTH_CHECK_TIMEOUT", 5*time.Second),
+	)
+	monitor.OnChange(func(name string, healthy bool) {
+		s.SetServing(name, healthy)
+		if name == healthcheck.Overall {
+			s.SetServing(aiwpb.AiWrapperService_ServiceDesc.ServiceName, healthy)
+			s.SetServing(aiwpbv3.AiWrapperService_ServiceDesc.ServiceName, healthy)
+		}
+	})
+	monitor.Start(ctx)
+
+	// Application service (use cases)
+	opts := []usecase.Option{usecase.WithHealth(monitor), usecase.WithUploadLimits(limits)}
+	if n := env.Int("LLM_MAX_IN_FLIGHT", 4); n > 0 {
+		opts = append(opts, usecase.WithLLMLimiter(admission.New("llm_queue",
+			n,
+			env.Int("LLM_MAX_QUEUE", 32),
+			env.Duration("LLM_QUEUE_TIMEOUT", 2*time.Minute),
+		)))
+	}
+	if env.Bool("CACHE_ENABLED", true) {
+		opts = append(opts, usecase.WithCache(newCache(ctx)))
+	}
+	currency := env.String("DEFAULT_CURRENCY", domain.DefaultCurrency)
+	if _, ok := domain.CurrencyExponent(currency); !ok {
+		log.Fatalf("unknown DEFAULT_CURRENCY %q (want an ISO 4217 code)", currency)
+	}
+	opts = append(opts, usecase.WithDefaultCurrency(currency))
+	loc, err := time.LoadLocation(env.String("DEFAULT_TIMEZONE", "Asia/Bangkok"))
+	if err != nil {
+		log.Fatalf("invalid DEFAULT_TIMEZONE: %v", err)
+	}
+	opts = append(opts, usecase.WithTimezone(loc))
+	switch fb := env.String("LLM_FALLBACK", "rules"); fb {
+	case "rules":
+		opts = append(opts, usecase.WithFallback(rules.NewParser(loc)))
+	case "none":
+	default:
+		log.Fatalf("unknown LLM_FALLBACK %q (want rules or none)", fb)
+	}
+	if env.Bool("IMAGE_PREPROCESS", true) {
+		opts = append(opts, usecase.WithImagePreprocessor(imageproc.NewPreprocessor()))
+	}
+	aiSvc := usecase.NewAIService(ocrCli, llmAdapter, opts...)
 	grpc.RegisterAIWrapperServer(s.Server, aiSvc)
+	grpc.RegisterAIWrapperServerV3(s.Server, usecase.NewAIServiceV3(aiSvc))
+
+	// Metrics (expvar JSON at /debug/vars)
+	metricsSrv := newMetricsServer(env.String("METRICS_ADDR", ":9090"))
+	go func() {
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("metrics server error: %v", err)
+		}
+	}()
 
 	// Start
 	go func() {
@@ -40,12 +126,55 @@ func main() {
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 	log.Println("Shutting down...")
+	cancel()
 	s.Stop()
+	_ = metricsSrv.Close()
+}
+
+// llmBackend is what every LLM adapter provides.
+type llmBackend interface {
+	ports.OllamaPort
+	ports.HealthPort
+}
+
+// newLLMBackend picks the transaction extraction backend; the returned name
+// is also the dependency name reported by health checks. Background work
+// stops when ctx is done.
+func newLLMBackend(ctx context.Context, kind string) (string, llmBackend) {
+	switch kind {
+	case "ollama":
+		return "ollama", ollama.NewOllamaAdapter(ctx)
+	case "openai":
+		return "openai", openai.NewChatAdapter()
+	default:
+		log.Fatalf("unknown LLM_BACKEND %q (want ollama or openai)", kind)
+		return "", nil
+	}
 }
 
-func env(k, def string) string {
-	if v := os.Getenv(k); v != "" {
-		return v
+// newCache is an in-memory LRU, backed by a disk store when CACHE_DIR is set.
+func newCache(ctx context.Context) cache.Store {
+	ttl := env.Duration("CACHE_TTL", 24*time.Hour)
+	size := env.Int("CACHE_SIZE", 1024)
+	if size < 0 {
+		log.Fatalf("invalid CACHE_SIZE %d (want 0 or more)", size)
+	}
+	mem := cache.NewLRU(size, ttl)
+
+	dir := os.Getenv("CACHE_DIR")
+	if dir == "" {
+		return mem
 	}
-	return def
+	disk, err := cache.NewDisk(ctx, dir, ttl)
+	if err != nil {
+		log.Printf("disk cache disabled: %v", err)
+		return mem
+	}
+	return cache.Tiered{mem, disk}
+}
+
+func newMetricsServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return &http.Server{Addr: addr, Handler: mux}
 }
@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	aiwpbv3 "github.com/cp25sy5-modjot/ai-wrapper-service/proto/gen/ai/v3"
 	aiwpb "github.com/cp25sy5-modjot/proto/gen/ai/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -11,3 +12,8 @@ func RegisterAIWrapperServer(s *grpc.Server, impl aiwpb.AiWrapperServiceServer)
 	aiwpb.RegisterAiWrapperServiceServer(s, impl)
 	reflection.Register(s)
 }
+
+// RegisterAIWrapperServerV3 registers the ai.v3 service next to the v2 one.
+func RegisterAIWrapperServerV3(s *grpc.Server, impl aiwpbv3.AiWrapperServiceServer) {
+	aiwpbv3.RegisterAiWrapperServiceServer(s, impl)
+}
@@ -0,0 +1,375 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// detectSize is the longer side of the working copy used to find the paper.
+const detectSize = 512
+
+// Confidence limits of the document detector; outside them the photo is
+// left as it is.
+const (
+	minPaperArea = 0.10 // fraction of the frame the paper must cover
+	maxPaperArea = 0.90 // above this there is no background worth cropping
+	minQuadFill  = 0.85 // paper pixels / quadrilateral area
+	minPaperLift = 20   // paper must be this much brighter than the background
+)
+
+// Deskew search range and step, in degrees.
+const (
+	maxSkew     = 5.0
+	skewStep    = 0.25
+	minSkewFix  = 0.5
+	deskewSize  = 1024
+	maxSkewDots = 200_000
+)
+
+type point struct{ X, Y float64 }
+
+// straighten crops the receipt out of the background, warps it to a flat
+// rectangle and removes a small leftover rotation. ok is false when no
+// confident paper boundary was found; img is then returned unchanged.
+func straighten(img *image.RGBA) (*image.RGBA, float64, bool) {
+	quad, ok := detectDocument(img)
+	if !ok {
+		return img, 0, false
+	}
+	flat := warpQuad(img, quad)
+	angle := skewAngle(flat)
+	if math.Abs(angle) >= minSkewFix {
+		flat = rotate(flat, angle)
+	}
+	return flat, angle, true
+}
+
+// detectDocument finds the corners (top-left, top-right, bottom-right,
+// bottom-left) of the bright paper on a darker background.
+func detectDocument(img *image.RGBA) ([4]point, bool) {
+	g, scale := grayWorkCopy(img, detectSize)
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+
+	var hist [256]int
+	for _, v := range g.Pix {
+		hist[v]++
+	}
+	t := otsu(&hist, len(g.Pix))
+
+	// the paper is the largest connected bright region
+	comp := largestComponent(g, t)
+	area := float64(len(comp)) / float64(w*h)
+	if area < minPaperArea || area > maxPaperArea {
+		return [4]point{}, false
+	}
+
+	var in, out float64
+	mask := make([]bool, w*h)
+	for _, i := range comp {
+		mask[i] = true
+		in += float64(g.Pix[i])
+	}
+	for i, v := range g.Pix {
+		if !mask[i] {
+			out += float64(v)
+		}
+	}
+	if in/float64(len(comp))-out/float64(w*h-len(comp)) < minPaperLift {
+		return [4]point{}, false
+	}
+
+	// extreme points along the diagonals are the corners of a roughly
+	// upright quadrilateral
+	var q [4]point
+	best := [4]float64{math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, i := range comp {
+		x, y := float64(i%w), float64(i/w)
+		if s := x + y; s < best[0] {
+			best[0], q[0] = s, point{x, y}
+		}
+		if d := x - y; d > best[1] {
+			best[1], q[1] = d, point{x, y}
+		}
+		if s := x + y; s > best[2] {
+			best[2], q[2] = s, point{x, y}
+		}
+		if d := y - x; d > best[3] {
+			best[3], q[3] = d, point{x, y}
+		}
+	}
+	if !convex(q) {
+		return [4]point{}, false
+	}
+	if fill := float64(len(comp)) / quadArea(q); fill < minQuadFill || fill > 1.1 {
+		return [4]point{}, false
+	}
+
+	for k := range q {
+		q[k] = point{(q[k].X + 0.5) * scale, (q[k].Y + 0.5) * scale}
+	}
+	return q, true
+}
+
+func grayWorkCopy(img *image.RGBA, size int) (*image.Gray, float64) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	scale := 1.0
+	if long := max(w, h); long > size {
+		scale = float64(long) / float64(size)
+		w, h = max(1, int(float64(w)/scale)), max(1, int(float64(h)/scale))
+	}
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	xdraw.ApproxBiLinear.Scale(g, g.Rect, img, img.Rect, xdraw.Src, nil)
+	return g, scale
+}
+
+// otsu returns the threshold that best separates the histogram in two classes.
+func otsu(hist *[256]int, n int) uint8 {
+	var total float64
+	for v, c := range hist {
+		total += float64(v * c)
+	}
+	var sumB, wB, bestVar float64
+	best := 0
+	for v, c := range hist {
+		wB += float64(c)
+		if wB == 0 {
+			continue
+		}
+		wF := float64(n) - wB
+		if wF == 0 {
+			break
+		}
+		sumB += float64(v * c)
+		mB, mF := sumB/wB, (total-sumB)/wF
+		if between := wB * wF * (mB - mF) * (mB - mF); between > bestVar {
+			bestVar, best = between, v
+		}
+	}
+	return uint8(best)
+}
+
+// largestComponent returns the pixel indexes of the largest 4-connected
+// region brighter than t.
+func largestComponent(g *image.Gray, t uint8) []int {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	seen := make([]bool, w*h)
+	var best, comp, stack []int
+	for start := range g.Pix {
+		if seen[start] || g.Pix[start] <= t {
+			continue
+		}
+		comp = comp[:0]
+		stack = append(stack[:0], start)
+		seen[start] = true
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			comp = append(comp, i)
+			x, y := i%w, i/w
+			for _, j := range [4]int{i - 1, i + 1, i - w, i + w} {
+				switch {
+				case j == i-1 && x == 0, j == i+1 && x == w-1, j == i-w && y == 0, j == i+w && y == h-1:
+					continue
+				}
+				if !seen[j] && g.Pix[j] > t {
+					seen[j] = true
+					stack = append(stack, j)
+				}
+			}
+		}
+		if len(comp) > len(best) {
+			best = append(best[:0], comp...)
+		}
+	}
+	return best
+}
+
+func convex(q [4]point) bool {
+	sign := 0.0
+	for k := range q {
+		a, b, c := q[k], q[(k+1)%4], q[(k+2)%4]
+		cross := (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+		if cross == 0 || (sign != 0 && (cross > 0) != (sign > 0)) {
+			return false
+		}
+		sign = cross
+	}
+	return true
+}
+
+func quadArea(q [4]point) float64 {
+	var a float64
+	for k := range q {
+		p, n := q[k], q[(k+1)%4]
+		a += p.X*n.Y - n.X*p.Y
+	}
+	return math.Abs(a) / 2
+}
+
+func dist(a, b point) float64 { return math.Hypot(a.X-b.X, a.Y-b.Y) }
+
+// warpQuad maps the quadrilateral onto an upright rectangle sized after its
+// longest edges.
+func warpQuad(img *image.RGBA, q [4]point) *image.RGBA {
+	w := int(math.Round(math.Max(dist(q[0], q[1]), dist(q[3], q[2]))))
+	h := int(math.Round(math.Max(dist(q[0], q[3]), dist(q[1], q[2]))))
+	rect := [4]point{{0, 0}, {float64(w), 0}, {float64(w), float64(h)}, {0, float64(h)}}
+	H, ok := homography(rect, q)
+	if !ok || w < 1 || h < 1 {
+		return img
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			fx, fy := float64(x)+0.5, float64(y)+0.5
+			d := H[6]*fx + H[7]*fy + 1
+			sx := (H[0]*fx + H[1]*fy + H[2]) / d
+			sy := (H[3]*fx + H[4]*fy + H[5]) / d
+			dst.SetRGBA(x, y, bilinear(img, sx-0.5, sy-0.5))
+		}
+	}
+	return dst
+}
+
+// homography solves the 3x3 projective transform (h33 = 1) taking each
+// from[k] to to[k].
+func homography(from, to [4]point) ([8]float64, bool) {
+	var a [8][9]float64
+	for k := 0; k < 4; k++ {
+		x, y, u, v := from[k].X, from[k].Y, to[k].X, to[k].Y
+		a[2*k] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*k+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+	// Gaussian elimination with partial pivoting
+	for c := 0; c < 8; c++ {
+		p := c
+		for r := c + 1; r < 8; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[p][c]) {
+				p = r
+			}
+		}
+		if math.Abs(a[p][c]) < 1e-12 {
+			return [8]float64{}, false
+		}
+		a[c], a[p] = a[p], a[c]
+		for r := 0; r < 8; r++ {
+			if r == c {
+				continue
+			}
+			f := a[r][c] / a[c][c]
+			for k := c; k < 9; k++ {
+				a[r][k] -= f * a[c][k]
+			}
+		}
+	}
+	var h [8]float64
+	for k := range h {
+		h[k] = a[k][8] / a[k][k]
+	}
+	return h, true
+}
+
+// skewAngle estimates the rotation of the text lines in degrees by finding
+// the angle whose horizontal projection profile is the most peaked.
+func skewAngle(img *image.RGBA) float64 {
+	g, _ := grayWorkCopy(img, deskewSize)
+	w := g.Rect.Dx()
+
+	var hist [256]int
+	for _, v := range g.Pix {
+		hist[v]++
+	}
+	t := otsu(&hist, len(g.Pix))
+
+	var dots []point
+	step := 1
+	if dark := len(g.Pix) - countAbove(&hist, t); dark > maxSkewDots {
+		step = dark/maxSkewDots + 1
+	}
+	n := 0
+	for i, v := range g.Pix {
+		if v <= t {
+			if n%step == 0 {
+				dots = append(dots, point{float64(i % w), float64(i / w)})
+			}
+			n++
+		}
+	}
+	if len(dots) == 0 {
+		return 0
+	}
+
+	bestAngle, bestScore := 0.0, -1.0
+	rows := make(map[int]int)
+	for a := -maxSkew; a <= maxSkew+1e-9; a += skewStep {
+		sin, cos := math.Sincos(a * math.Pi / 180)
+		clear(rows)
+		for _, d := range dots {
+			rows[int(math.Round(d.Y*cos-d.X*sin))]++
+		}
+		var score float64
+		for _, c := range rows {
+			score += float64(c * c)
+		}
+		if score > bestScore {
+			bestAngle, bestScore = a, score
+		}
+	}
+	return bestAngle
+}
+
+func countAbove(hist *[256]int, t uint8) int {
+	n := 0
+	for v := int(t) + 1; v < 256; v++ {
+		n += hist[v]
+	}
+	return n
+}
+
+// rotate turns img by -deg degrees around its centre, undoing a skew of
+// deg; uncovered corners are filled white like the paper.
+func rotate(img *image.RGBA, deg float64) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	sin, cos := math.Sincos(deg * math.Pi / 180)
+	cx, cy := float64(w)/2, float64(h)/2
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			sx := dx*cos - dy*sin + cx - 0.5
+			sy := dx*sin + dy*cos + cy - 0.5
+			dst.SetRGBA(x, y, bilinear(img, sx, sy))
+		}
+	}
+	return dst
+}
+
+var white = color.RGBA{255, 255, 255, 255}
+
+// bilinear samples img at a fractional pixel position.
+func bilinear(img *image.RGBA, x, y float64) color.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if x < -0.5 || y < -0.5 || x > float64(w)-0.5 || y > float64(h)-0.5 {
+		return white
+	}
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	x0, y0 = min(max(x0, 0), w-1), min(max(y0, 0), h-1)
+	x1, y1 := min(x0+1, w-1), min(y0+1, h-1)
+
+	p00 := img.Pix[img.PixOffset(x0, y0):]
+	p10 := img.Pix[img.PixOffset(x1, y0):]
+	p01 := img.Pix[img.PixOffset(x0, y1):]
+	p11 := img.Pix[img.PixOffset(x1, y1):]
+	var c [4]uint8
+	for k := range c {
+		top := float64(p00[k])*(1-fx) + float64(p10[k])*fx
+		bot := float64(p01[k])*(1-fx) + float64(p11[k])*fx
+		c[k] = uint8(top*(1-fy) + bot*fy + 0.5)
+	}
+	return color.RGBA{c[0], c[1], c[2], c[3]}
+}
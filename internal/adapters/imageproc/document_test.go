@@ -0,0 +1,101 @@
+package imageproc
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// apply maps p through the homography h.
+func apply(h [8]float64, p point) point {
+	d := h[6]*p.X + h[7]*p.Y + 1
+	return point{(h[0]*p.X + h[1]*p.Y + h[2]) / d, (h[3]*p.X + h[4]*p.Y + h[5]) / d}
+}
+
+func near(a, b point, tol float64) bool { return dist(a, b) <= tol }
+
+func TestHomographyRoundTrip(t *testing.T) {
+	rect := [4]point{{0, 0}, {600, 0}, {600, 800}, {0, 800}}
+	quad := [4]point{{112, 87}, {690, 131}, {655, 940}, {74, 902}}
+
+	there, ok := homography(rect, quad)
+	if !ok {
+		t.Fatal("homography(rect, quad) not solvable")
+	}
+	back, ok := homography(quad, rect)
+	if !ok {
+		t.Fatal("homography(quad, rect) not solvable")
+	}
+	for k := range rect {
+		if got := apply(there, rect[k]); !near(got, quad[k], 1e-6) {
+			t.Errorf("corner %d maps to %v, want %v", k, got, quad[k])
+		}
+	}
+	// any point, not just the corners, comes back where it started
+	for _, p := range []point{{300, 400}, {10, 790}, {599, 1}} {
+		if got := apply(back, apply(there, p)); !near(got, p, 1e-6) {
+			t.Errorf("%v round-trips to %v", p, got)
+		}
+	}
+}
+
+func TestHomographyDegenerate(t *testing.T) {
+	rect := [4]point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	line := [4]point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}
+	if _, ok := homography(line, rect); ok {
+		t.Error("homography from collinear points solved, want not ok")
+	}
+}
+
+// paperPhoto is a bright sheet at r on a dark background.
+func paperPhoto(w, h int, r image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(40)
+			if (image.Point{x, y}).In(r) {
+				v = 230
+			}
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func TestDetectDocument(t *testing.T) {
+	img := paperPhoto(800, 1000, image.Rect(100, 150, 700, 850))
+	q, ok := detectDocument(img)
+	if !ok {
+		t.Fatal("paper on a dark background not detected")
+	}
+	want := [4]point{{100, 150}, {700, 150}, {700, 850}, {100, 850}}
+	for k := range want {
+		if !near(q[k], want[k], 6) { // one pixel of the 512px working copy
+			t.Errorf("corner %d = %v, want about %v", k, q[k], want[k])
+		}
+	}
+
+	flat, _, ok := straighten(img)
+	if w, h := flat.Rect.Dx(), flat.Rect.Dy(); !ok || math.Abs(float64(w-600)) > 8 || math.Abs(float64(h-700)) > 8 {
+		t.Errorf("straighten = %dx%d (ok %v), want the 600x700 sheet", w, h, ok)
+	}
+}
+
+func TestNoDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.RGBA
+	}{
+		{"blank frame", paperPhoto(800, 1000, image.Rectangle{})},
+		{"paper fills the frame", paperPhoto(800, 1000, image.Rect(0, 0, 800, 1000))},
+		{"paper too small", paperPhoto(800, 1000, image.Rect(380, 480, 420, 520))},
+		{"tiny image", paperPhoto(3, 3, image.Rect(1, 1, 2, 2))},
+	}
+	for _, tt := range tests {
+		got, angle, ok := straighten(tt.img)
+		if ok || got != tt.img || angle != 0 {
+			t.Errorf("%s: straighten ok = %v, want the image back unchanged", tt.name, ok)
+		}
+	}
+}
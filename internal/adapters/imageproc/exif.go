@@ -0,0 +1,83 @@
+package imageproc
+
+import "encoding/binary"
+
+// EXIF orientation values (tag 0x0112).
+const (
+	orientNormal     = 1
+	orientFlipH      = 2
+	orientRotate180  = 3
+	orientFlipV      = 4
+	orientTranspose  = 5
+	orientRotate90   = 6 // rotate 90° clockwise to display
+	orientTransverse = 7
+	orientRotate270  = 8 // rotate 90° counter-clockwise to display
+)
+
+// jpegOrientation reads the EXIF orientation of a JPEG. It returns
+// orientNormal when the file has no EXIF block or the tag is missing.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return orientNormal
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return orientNormal
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 || marker == 0xFF {
+			i++ // standalone marker or fill byte
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return orientNormal // image data starts, no EXIF before it
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return orientNormal
+		}
+		seg := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + size
+	}
+	return orientNormal
+}
+
+// tiffOrientation looks for the orientation tag in IFD0 of a TIFF header.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return orientNormal
+	}
+	var bo binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return orientNormal
+	}
+
+	ifd := int(bo.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return orientNormal
+	}
+	n := int(bo.Uint16(tiff[ifd:]))
+	for k := 0; k < n; k++ {
+		e := ifd + 2 + k*12
+		if e+12 > len(tiff) {
+			break
+		}
+		if bo.Uint16(tiff[e:]) != 0x0112 {
+			continue
+		}
+		// SHORT value stored inline in the first two bytes of the value field
+		if o := int(bo.Uint16(tiff[e+8:])); o >= orientNormal && o <= orientRotate270 {
+			return o
+		}
+		break
+	}
+	return orientNormal
+}
@@ -0,0 +1,154 @@
+// Package imageproc prepares phone photos for OCR: a quality gate, EXIF
+// orientation, downscaling, optional document cropping/perspective correction
+// and grayscale/contrast clean-up, in pure Go.
+package imageproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/env"
+	_ "golang.org/x/image/webp" // register the decoder
+)
+
+// Config selects the preprocessing steps; each one can be turned off.
+type Config struct {
+	AutoOrient   bool // apply the EXIF orientation
+	MaxDimension int  // longest side in pixels, 0 keeps the original size
+	// DocumentDetect crops the receipt from the background, flattens its
+	// perspective and deskews it.
+	DocumentDetect  bool
+	Grayscale       bool
+	ContrastStretch bool
+	JPEGQuality     int
+	// QualityGate rejects photos failing Thresholds before any other step.
+	// It runs inside Prepare, so only when preprocessing is enabled
+	// (IMAGE_PREPROCESS); it is off by default because the thresholds have
+	// to be tuned on real uploads.
+	QualityGate bool
+	Thresholds  Thresholds
+}
+
+// rejections counts images refused by the quality gate, by reason.
+var rejections = expvar.NewMap("image_quality_rejections")
+
+type Preprocessor struct {
+	cfg Config
+}
+
+func NewPreprocessor() *Preprocessor {
+	return &Preprocessor{cfg: Config{
+		AutoOrient:      env.Bool("IMAGE_AUTO_ORIENT", true),
+		MaxDimension:    env.Int("IMAGE_MAX_DIMENSION", 2048),
+		DocumentDetect:  env.Bool("IMAGE_DOCUMENT_DETECT", false),
+		Grayscale:       env.Bool("IMAGE_GRAYSCALE", false),
+		ContrastStretch: env.Bool("IMAGE_CONTRAST_STRETCH", false),
+		JPEGQuality:     env.Int("IMAGE_JPEG_QUALITY", 90),
+		QualityGate:     env.Bool("IMAGE_QUALITY_GATE", false),
+		Thresholds: Thresholds{
+			MinSharpness:   env.Float("IMAGE_MIN_SHARPNESS", 40),
+			MinBrightness:  env.Float("IMAGE_MIN_BRIGHTNESS", 40),
+			MaxBrightness:  env.Float("IMAGE_MAX_BRIGHTNESS", 250),
+			MinShortSide:   env.Int("IMAGE_MIN_SHORT_SIDE", 300),
+			MaxAspectRatio: env.Float("IMAGE_MAX_ASPECT_RATIO", 8),
+		},
+	}}
+}
+
+// Fingerprint implements ports.Fingerprinter.
+func (p *Preprocessor) Fingerprint() string { return fmt.Sprintf("%+v", p.cfg) }
+
+// Prepare decodes a JPEG, PNG or WebP, runs the configured steps and
+// re-encodes it in the same format (WebP as JPEG). PDFs, other formats and images that need no change are
+// returned as they are. Photos failing the quality gate are rejected with a
+// *domain.QualityError.
+func (p *Preprocessor) Prepare(ctx context.Context, data []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		// not something we can decode; let the OCR API decide
+		return data, nil
+	}
+	before := src.Bounds()
+
+	if p.cfg.QualityGate {
+		q := assess(src)
+		if err := p.cfg.Thresholds.check(q); err != nil {
+			var qe *domain.QualityError
+			if errors.As(err, &qe) {
+				rejections.Add(qe.Reason, 1)
+			}
+			log.Printf("image quality: %dx%d sharpness=%.1f brightness=%.1f: %v", q.Width, q.Height, q.Sharpness, q.Brightness, err)
+			return nil, err
+		}
+	}
+
+	var (
+		img   image.Image
+		steps []string
+	)
+	rgba, resized := downscale(toRGBA(src), p.cfg.MaxDimension)
+	if resized {
+		steps = append(steps, "resize")
+	}
+	if p.cfg.AutoOrient && format == "jpeg" {
+		if o := jpegOrientation(data); o != orientNormal {
+			rgba = orient(rgba, o)
+			steps = append(steps, fmt.Sprintf("orient(%d)", o))
+		}
+	}
+	if p.cfg.DocumentDetect {
+		if flat, skew, ok := straighten(rgba); ok {
+			rgba = flat
+			steps = append(steps, fmt.Sprintf("document(skew %.2f°)", skew))
+		}
+	}
+	img = rgba
+	if p.cfg.Grayscale {
+		img = grayscale(rgba)
+		steps = append(steps, "grayscale")
+	}
+	if p.cfg.ContrastStretch && stretchContrast(img) {
+		steps = append(steps, "contrast")
+	}
+	if len(steps) == 0 {
+		return data, nil
+	}
+
+	out, err := p.encode(img, format)
+	if err != nil {
+		return nil, fmt.Errorf("re-encode %s: %w", format, err)
+	}
+	after := img.Bounds()
+	log.Printf("image preprocess [%s]: %s %dx%d %dB -> %dx%d %dB in %v",
+		strings.Join(steps, " "), format,
+		before.Dx(), before.Dy(), len(data),
+		after.Dx(), after.Dy(), len(out),
+		time.Since(start).Round(time.Millisecond))
+	return out, nil
+}
+
+func (p *Preprocessor) encode(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.cfg.JPEGQuality})
+	}
+	return buf.Bytes(), err
+}
@@ -0,0 +1,103 @@
+package imageproc
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	xdraw "golang.org/x/image/draw"
+)
+
+// assessSize is the longer side images are scaled to before measuring blur,
+// so the sharpness score does not depend on the camera resolution.
+const assessSize = 1024
+
+// Thresholds of the quality gate; a zero value disables that check.
+type Thresholds struct {
+	MinSharpness   float64 // variance of the Laplacian at assessSize
+	MinBrightness  float64 // mean luminance 0..255
+	MaxBrightness  float64
+	MinShortSide   int // pixels
+	MaxAspectRatio float64
+}
+
+// Quality holds the measurements the gate decides on.
+type Quality struct {
+	Width, Height int
+	Sharpness     float64
+	Brightness    float64
+}
+
+func assess(img image.Image) Quality {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	sw, sh := w, h
+	if long := max(w, h); long > assessSize {
+		sw, sh = max(1, w*assessSize/long), max(1, h*assessSize/long)
+	}
+	gray := image.NewGray(image.Rect(0, 0, sw, sh))
+	if sw == w && sh == h {
+		draw.Draw(gray, gray.Rect, img, b.Min, draw.Src)
+	} else {
+		xdraw.ApproxBiLinear.Scale(gray, gray.Rect, img, b, xdraw.Src, nil)
+	}
+	return Quality{
+		Width:      w,
+		Height:     h,
+		Sharpness:  laplacianVariance(gray),
+		Brightness: meanLuma(gray),
+	}
+}
+
+// check returns the first failed threshold as a *domain.QualityError.
+func (t Thresholds) check(q Quality) error {
+	short, long := min(q.Width, q.Height), max(q.Width, q.Height)
+	switch {
+	case t.MinShortSide > 0 && short < t.MinShortSide:
+		return &domain.QualityError{Reason: domain.QualityTooSmall, Metric: "short_side_px", Value: float64(short), Threshold: float64(t.MinShortSide)}
+	case t.MaxAspectRatio > 0 && float64(long) > t.MaxAspectRatio*float64(short):
+		return &domain.QualityError{Reason: domain.QualityBadAspect, Metric: "aspect_ratio", Value: float64(long) / float64(max(short, 1)), Threshold: t.MaxAspectRatio}
+	case t.MinBrightness > 0 && q.Brightness < t.MinBrightness:
+		return &domain.QualityError{Reason: domain.QualityTooDark, Metric: "brightness", Value: q.Brightness, Threshold: t.MinBrightness}
+	case t.MaxBrightness > 0 && q.Brightness > t.MaxBrightness:
+		return &domain.QualityError{Reason: domain.QualityTooBright, Metric: "brightness", Value: q.Brightness, Threshold: t.MaxBrightness}
+	case t.MinSharpness > 0 && q.Sharpness < t.MinSharpness:
+		return &domain.QualityError{Reason: domain.QualityTooBlurry, Metric: "sharpness", Value: q.Sharpness, Threshold: t.MinSharpness}
+	}
+	return nil
+}
+
+// laplacianVariance is the variance of the 4-neighbour Laplacian; sharp text
+// has strong edges and scores high, motion or focus blur scores low.
+func laplacianVariance(g *image.Gray) float64 {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	if w < 3 || h < 3 {
+		return 0
+	}
+	var sum, sumSq float64
+	for y := 1; y < h-1; y++ {
+		row := g.Pix[y*g.Stride:]
+		up, down := g.Pix[(y-1)*g.Stride:], g.Pix[(y+1)*g.Stride:]
+		for x := 1; x < w-1; x++ {
+			v := float64(int(up[x]) + int(down[x]) + int(row[x-1]) + int(row[x+1]) - 4*int(row[x]))
+			sum += v
+			sumSq += v * v
+		}
+	}
+	n := float64((w - 2) * (h - 2))
+	mean := sum / n
+	return sumSq/n - mean*mean
+}
+
+func meanLuma(g *image.Gray) float64 {
+	if len(g.Pix) == 0 {
+		return 0
+	}
+	var sum int
+	for y := 0; y < g.Rect.Dy(); y++ {
+		for _, v := range g.Pix[y*g.Stride:][:g.Rect.Dx()] {
+			sum += int(v)
+		}
+	}
+	return float64(sum) / float64(g.Rect.Dx()*g.Rect.Dy())
+}
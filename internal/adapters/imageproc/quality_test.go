@@ -0,0 +1,123 @@
+package imageproc
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// defaultThresholds are NewPreprocessor's defaults.
+var defaultThresholds = Thresholds{
+	MinSharpness:   40,
+	MinBrightness:  40,
+	MaxBrightness:  250,
+	MinShortSide:   300,
+	MaxAspectRatio: 8,
+}
+
+// receiptImage draws rows of dark "text" dashes on paper of the given
+// luminance, like a photographed receipt.
+func receiptImage(w, h int, paper, ink uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := paper
+			if y%20 < 8 && x%12 < 7 {
+				v = ink
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+// gradientImage is a smooth left-to-right ramp with no edges at all, the
+// worst case of a blurred photo.
+func gradientImage(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(100 + 100*x/w)})
+		}
+	}
+	return img
+}
+
+func flatImage(w, h int, v uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = v
+	}
+	return img
+}
+
+func TestQualityGate(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		want string // domain.Quality* reason, "" when accepted
+	}{
+		{"sharp receipt", receiptImage(600, 900, 235, 20), ""},
+		{"large sharp receipt", receiptImage(1500, 3000, 235, 20), ""},
+		{"no edges", gradientImage(600, 900), domain.QualityTooBlurry},
+		{"dark", receiptImage(600, 900, 30, 0), domain.QualityTooDark},
+		{"blown out", flatImage(600, 900, 255), domain.QualityTooBright},
+		{"thumbnail", receiptImage(200, 250, 235, 20), domain.QualityTooSmall},
+		{"strip", receiptImage(3000, 300, 235, 20), domain.QualityBadAspect},
+	}
+	for _, tt := range tests {
+		q := assess(tt.img)
+		err := defaultThresholds.check(q)
+		var qe *domain.QualityError
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: rejected (sharpness %.1f, brightness %.1f): %v", tt.name, q.Sharpness, q.Brightness, err)
+		case tt.want != "" && (!errors.As(err, &qe) || qe.Reason != tt.want):
+			t.Errorf("%s: err = %v (sharpness %.1f, brightness %.1f), want %s", tt.name, err, q.Sharpness, q.Brightness, tt.want)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	q := assess(flatImage(2000, 1000, 128))
+	if q.Width != 2000 || q.Height != 1000 {
+		t.Errorf("size = %dx%d, want the original 2000x1000", q.Width, q.Height)
+	}
+	if q.Brightness != 128 || q.Sharpness != 0 {
+		t.Errorf("flat gray: brightness %.1f, sharpness %.1f; want 128 and 0", q.Brightness, q.Sharpness)
+	}
+
+	// measured at assessSize, so the camera resolution barely moves the score
+	small, large := assess(receiptImage(1000, 1000, 235, 20)), assess(receiptImage(2000, 2000, 235, 20))
+	if large.Sharpness < small.Sharpness/2 || large.Sharpness > small.Sharpness*2 {
+		t.Errorf("sharpness %.1f at 1000px but %.1f at 2000px", small.Sharpness, large.Sharpness)
+	}
+}
+
+func TestThresholdsCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		t    Thresholds
+		q    Quality
+		want string
+	}{
+		{"all pass", defaultThresholds, Quality{Width: 800, Height: 1200, Sharpness: 100, Brightness: 180}, ""},
+		{"size before blur", defaultThresholds, Quality{Width: 100, Height: 200, Sharpness: 1, Brightness: 180}, domain.QualityTooSmall},
+		{"brightness before blur", defaultThresholds, Quality{Width: 800, Height: 1200, Sharpness: 1, Brightness: 10}, domain.QualityTooDark},
+		{"at the limits", defaultThresholds, Quality{Width: 300, Height: 2400, Sharpness: 40, Brightness: 40}, ""},
+		{"zero disables", Thresholds{}, Quality{Width: 1, Height: 100, Sharpness: 0, Brightness: 0}, ""},
+	}
+	for _, tt := range tests {
+		err := tt.t.check(tt.q)
+		var qe *domain.QualityError
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: %v, want accepted", tt.name, err)
+		case tt.want != "" && (!errors.As(err, &qe) || qe.Reason != tt.want):
+			t.Errorf("%s: err = %v, want %s", tt.name, err, tt.want)
+		}
+	}
+}
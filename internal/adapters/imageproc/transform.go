@@ -0,0 +1,148 @@
+package imageproc
+
+import (
+	"image"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, img, b.Min, draw.Src)
+	return dst
+}
+
+// downscale shrinks img so its longer side is at most maxDim. It returns
+// img unchanged (and false) when it already fits.
+func downscale(img *image.RGBA, maxDim int) (*image.RGBA, bool) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if maxDim <= 0 || (w <= maxDim && h <= maxDim) {
+		return img, false
+	}
+	if w >= h {
+		w, h = maxDim, max(1, h*maxDim/w)
+	} else {
+		w, h = max(1, w*maxDim/h), maxDim
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Rect, img, img.Rect, xdraw.Src, nil)
+	return dst, true
+}
+
+// orient turns an image stored with the given EXIF orientation upright.
+func orient(img *image.RGBA, o int) *image.RGBA {
+	if o <= orientNormal || o > orientRotate270 {
+		return img
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dw, dh := w, h
+	if o >= orientTranspose {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch o {
+			case orientFlipH:
+				dx, dy = w-1-x, y
+			case orientRotate180:
+				dx, dy = w-1-x, h-1-y
+			case orientFlipV:
+				dx, dy = x, h-1-y
+			case orientTranspose:
+				dx, dy = y, x
+			case orientRotate90:
+				dx, dy = h-1-y, x
+			case orientTransverse:
+				dx, dy = h-1-y, w-1-x
+			case orientRotate270:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], img.Pix[img.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+func grayscale(img *image.RGBA) *image.Gray {
+	dst := image.NewGray(img.Rect)
+	draw.Draw(dst, dst.Rect, img, img.Rect.Min, draw.Src)
+	return dst
+}
+
+// stretchContrast maps the 1st..99th percentile of luminance onto the full
+// 0..255 range, which lifts faded thermal prints and dim photos. It reports
+// false when the image already spans (almost) the full range.
+func stretchContrast(img image.Image) bool {
+	var (
+		hist [256]int
+		n    int
+	)
+	switch m := img.(type) {
+	case *image.Gray:
+		for _, v := range m.Pix {
+			hist[v]++
+		}
+		n = len(m.Pix)
+	case *image.RGBA:
+		for i := 0; i+3 < len(m.Pix); i += 4 {
+			hist[luma(m.Pix[i], m.Pix[i+1], m.Pix[i+2])]++
+		}
+		n = len(m.Pix) / 4
+	default:
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+
+	lo, hi := percentile(&hist, n, 0.01), percentile(&hist, n, 0.99)
+	if hi-lo < 8 || (lo <= 5 && hi >= 250) {
+		return false // flat image, or nothing to gain
+	}
+	var lut [256]uint8
+	for v := range lut {
+		switch {
+		case v <= lo:
+			lut[v] = 0
+		case v >= hi:
+			lut[v] = 255
+		default:
+			lut[v] = uint8((v - lo) * 255 / (hi - lo))
+		}
+	}
+
+	switch m := img.(type) {
+	case *image.Gray:
+		for i, v := range m.Pix {
+			m.Pix[i] = lut[v]
+		}
+	case *image.RGBA:
+		for i := 0; i+3 < len(m.Pix); i += 4 {
+			m.Pix[i], m.Pix[i+1], m.Pix[i+2] = lut[m.Pix[i]], lut[m.Pix[i+1]], lut[m.Pix[i+2]]
+		}
+	}
+	return true
+}
+
+func luma(r, g, b uint8) uint8 {
+	return uint8((19595*uint32(r) + 38470*uint32(g) + 7471*uint32(b) + 1<<15) >> 16)
+}
+
+func percentile(hist *[256]int, n int, p float64) int {
+	target := int(float64(n) * p)
+	sum := 0
+	for v, c := range hist {
+		sum += c
+		if sum > target {
+			return v
+		}
+	}
+	return 255
+}
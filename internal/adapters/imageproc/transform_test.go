@@ -0,0 +1,123 @@
+package imageproc
+
+import (
+	"encoding/binary"
+	"image"
+	"reflect"
+	"testing"
+)
+
+// letterImage makes an RGBA image whose pixels carry the letters of rows in
+// their red channel, so a transform can be read back as text.
+func letterImage(rows ...string) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x := range len(row) {
+			img.Pix[img.PixOffset(x, y)] = row[x]
+			img.Pix[img.PixOffset(x, y)+3] = 255
+		}
+	}
+	return img
+}
+
+func letters(img *image.RGBA) []string {
+	rows := make([]string, img.Rect.Dy())
+	for y := range rows {
+		row := make([]byte, img.Rect.Dx())
+		for x := range row {
+			row[x] = img.Pix[img.PixOffset(x, y)]
+		}
+		rows[y] = string(row)
+	}
+	return rows
+}
+
+func TestOrient(t *testing.T) {
+	// stored as "abc/def", each orientation turned upright
+	tests := []struct {
+		o    int
+		want []string
+	}{
+		{orientNormal, []string{"abc", "def"}},
+		{orientFlipH, []string{"cba", "fed"}},
+		{orientRotate180, []string{"fed", "cba"}},
+		{orientFlipV, []string{"def", "abc"}},
+		{orientTranspose, []string{"ad", "be", "cf"}},
+		{orientRotate90, []string{"da", "eb", "fc"}},
+		{orientTransverse, []string{"fc", "eb", "da"}},
+		{orientRotate270, []string{"cf", "be", "ad"}},
+		{9, []string{"abc", "def"}}, // invalid values are ignored
+	}
+	for _, tt := range tests {
+		if got := letters(orient(letterImage("abc", "def"), tt.o)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("orient %d = %v, want %v", tt.o, got, tt.want)
+		}
+	}
+}
+
+// exifJPEG is the start of a JPEG with an APP1 EXIF segment holding the
+// orientation tag in IFD0.
+func exifJPEG(bo binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if bo == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	bo.PutUint16(tiff[2:], 42)
+	bo.PutUint32(tiff[4:], 8)
+	bo.PutUint16(tiff[8:], 1)       // one entry
+	bo.PutUint16(tiff[10:], 0x0112) // orientation
+	bo.PutUint16(tiff[12:], 3)      // SHORT
+	bo.PutUint32(tiff[14:], 1)
+	bo.PutUint16(tiff[18:], orientation)
+
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(seg)+2))
+	out = append(out, seg...)
+	return append(out, 0xFF, 0xDA, 0, 2)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"big endian", exifJPEG(binary.BigEndian, orientRotate90), orientRotate90},
+		{"little endian", exifJPEG(binary.LittleEndian, orientRotate270), orientRotate270},
+		{"out of range value", exifJPEG(binary.BigEndian, 12), orientNormal},
+		{"no EXIF", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}, orientNormal},
+		{"not a JPEG", []byte("\x89PNG\r\n\x1a\n"), orientNormal},
+		{"truncated", exifJPEG(binary.BigEndian, orientRotate90)[:20], orientNormal},
+	}
+	for _, tt := range tests {
+		if got := jpegOrientation(tt.data); got != tt.want {
+			t.Errorf("%s: orientation = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDownscale(t *testing.T) {
+	tests := []struct {
+		w, h, maxDim int
+		wantW, wantH int
+	}{
+		{4000, 3000, 2048, 2048, 1536},
+		{3000, 4000, 2048, 1536, 2048},
+		{1000, 800, 2048, 1000, 800},
+		{4000, 3000, 0, 4000, 3000},
+		{5000, 2, 1000, 1000, 1},
+	}
+	for _, tt := range tests {
+		img := image.NewRGBA(image.Rect(0, 0, tt.w, tt.h))
+		got, resized := downscale(img, tt.maxDim)
+		if got.Rect.Dx() != tt.wantW || got.Rect.Dy() != tt.wantH {
+			t.Errorf("downscale %dx%d to %d = %dx%d, want %dx%d", tt.w, tt.h, tt.maxDim, got.Rect.Dx(), got.Rect.Dy(), tt.wantW, tt.wantH)
+		}
+		if want := tt.wantW != tt.w; resized != want || (!resized && got != img) {
+			t.Errorf("downscale %dx%d to %d: resized = %v, want %v", tt.w, tt.h, tt.maxDim, resized, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"expvar"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// itemFixes counts item corrections: "price_fixed" (the model gave the unit
+// price as the line price), "unit_price_fixed" (quantity x unit price did
+// not match the line price) and "collapsed" duplicate lines.
+var itemFixes = expvar.NewMap("llm_item_fixes")
+
+// unitPattern matches the units receipts print after a quantity or weight.
+const unitPattern = `kgs?|gm?|grams?|ltr|l|ml|pcs?|ea|ชิ้น|หน่วย|กก\.?|กรัม|ลิตร|มล\.?`
+
+// quantityRe matches a quantity line such as "2@35.00", "2 x 35.00",
+// "0.512 kg @ 89.00" or "1.25KG X 120.00/KG". An "x" needs a decimal price
+// after it so sizes like "3x4" are left alone.
+var quantityRe = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(` + unitPattern + `)?\s*(?:@\s*(\d[\d,]*(?:\.\d+)?)|[x×]\s*(\d[\d,]*\.\d+))(?:\s*/\s*(?:` + unitPattern + `))?`)
+
+// units maps printed units to domain.Units with the factor turning the
+// printed quantity into that unit.
+var units = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"": {domain.UnitPiece, 1}, "pc": {domain.UnitPiece, 1}, "pcs": {domain.UnitPiece, 1},
+	"ea": {domain.UnitPiece, 1}, "piece": {domain.UnitPiece, 1}, "ชิ้น": {domain.UnitPiece, 1}, "หน่วย": {domain.UnitPiece, 1},
+	"kg": {domain.UnitKg, 1}, "kgs": {domain.UnitKg, 1}, "กก": {domain.UnitKg, 1}, "กก.": {domain.UnitKg, 1},
+	"g": {domain.UnitKg, 0.001}, "gm": {domain.UnitKg, 0.001}, "gram": {domain.UnitKg, 0.001},
+	"grams": {domain.UnitKg, 0.001}, "กรัม": {domain.UnitKg, 0.001},
+	"l": {domain.UnitLitre, 1}, "ltr": {domain.UnitLitre, 1}, "litre": {domain.UnitLitre, 1}, "ลิตร": {domain.UnitLitre, 1},
+	"ml": {domain.UnitLitre, 0.001}, "มล": {domain.UnitLitre, 0.001}, "มล.": {domain.UnitLitre, 0.001},
+}
+
+// Quantity is what a quantity or weighed-item line says.
+type Quantity struct {
+	Qty       float64
+	UnitPrice domain.Amount
+	Unit      string // one of domain.Units
+}
+
+// SplitQuantity finds a quantity expression in line and returns the line
+// without it. ok is false when the line has none.
+func SplitQuantity(line string) (rest string, q Quantity, ok bool) {
+	loc := quantityRe.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line, Quantity{}, false
+	}
+	p := loc[6:8] // after "@", else after "x"
+	if p[0] < 0 {
+		p = loc[8:10]
+	}
+	qty, err1 := strconv.ParseFloat(line[loc[2]:loc[3]], 64)
+	price, err2 := domain.ParseAmount(line[p[0]:p[1]])
+	if err1 != nil || err2 != nil || qty <= 0 {
+		return line, Quantity{}, false
+	}
+	var printed string
+	if loc[4] >= 0 {
+		printed = line[loc[4]:loc[5]]
+	}
+	u, known := units[strings.ToLower(printed)]
+	if !known {
+		u = units[""]
+	}
+	q = Quantity{Qty: qty * u.factor, UnitPrice: atLeastCents(price).Div(u.factor), Unit: u.unit}
+	rest = strings.Join(strings.Fields(line[:loc[0]]+" "+line[loc[1]:]), " ")
+	return rest, q, true
+}
+
+// FinishItems fills in item defaults, checks quantity x unit price against
+// the line price and collapses consecutive duplicate lines of a product.
+func FinishItems(items []domain.TransactionItem) []domain.TransactionItem {
+	for i := range items {
+		fillDefaults(&items[i])
+	}
+	return collapseDuplicates(items)
+}
+
+func fillDefaults(item *domain.TransactionItem) {
+	if item.Category == "" {
+		item.Category = DefaultCategory
+	}
+
+	u, known := units[strings.ToLower(strings.TrimSpace(item.Unit))]
+	if !known {
+		u = units[""]
+	}
+	item.Unit = u.unit
+	if item.Quantity <= 0 {
+		item.Quantity = 1
+	} else if u.factor != 1 {
+		item.Quantity *= u.factor
+		item.UnitPrice = atLeastCents(item.UnitPrice).Div(u.factor)
+	}
+	if item.UnitPrice.Sign() <= 0 {
+		item.UnitPrice = atLeastCents(item.Price).Div(item.Quantity)
+		return
+	}
+	if item.Price.Sign() <= 0 {
+		item.Price = atLeastCents(item.UnitPrice).Mul(item.Quantity)
+		return
+	}
+
+	if withinTolerance(item.UnitPrice.Mul(item.Quantity), item.Price) {
+		return
+	}
+	if item.Quantity != 1 && withinTolerance(item.UnitPrice, item.Price) {
+		// the unit price was given as the line price
+		item.Price = atLeastCents(item.UnitPrice).Mul(item.Quantity)
+		itemFixes.Add("price_fixed", 1)
+		return
+	}
+	// the line price is what was paid, so it wins
+	item.UnitPrice = atLeastCents(item.Price).Div(item.Quantity)
+	itemFixes.Add("unit_price_fixed", 1)
+}
+
+// collapseDuplicates merges consecutive lines of the same product at the
+// same unit price, as printed when an item is scanned more than once.
+func collapseDuplicates(items []domain.TransactionItem) []domain.TransactionItem {
+	if len(items) < 2 {
+		return items
+	}
+	out := items[:1]
+	for _, item := range items[1:] {
+		last := &out[len(out)-1]
+		if item.Title == last.Title && item.Unit == last.Unit && item.Category == last.Category &&
+			item.UnitPrice.Sub(last.UnitPrice).IsZero() {
+			last.Quantity += item.Quantity
+			last.Price = last.Price.Add(item.Price)
+			itemFixes.Add("collapsed", 1)
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// withinTolerance allows for satang rounding of weighed items. The currency
+// is not settled yet, so the tolerance is that of a 2-decimal one.
+func withinTolerance(got, want domain.Amount) bool {
+	tol := atLeastCents(want).Abs().Mul(0.01)
+	if t := domain.ReconcileTolerance(""); t.Cmp(tol) > 0 {
+		tol = t
+	}
+	return got.Sub(want).Abs().Cmp(tol) <= 0
+}
+
+// atLeastCents keeps two decimals when dividing or multiplying amounts the
+// model wrote without them ("70"); SetCurrency settles the final precision.
+func atLeastCents(a domain.Amount) domain.Amount {
+	if a.Exp < 2 {
+		return a.Rescale(2)
+	}
+	return a
+}
@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+func amount(t *testing.T, s string) domain.Amount {
+	t.Helper()
+	a, err := domain.ParseAmount(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func sameAmount(a, b domain.Amount) bool { return a.Sub(b).IsZero() }
+
+func TestSplitQuantity(t *testing.T) {
+	tests := []struct {
+		line      string
+		ok        bool
+		rest      string
+		qty       float64
+		unitPrice string
+		unit      string
+	}{
+		{"2@35.00", true, "", 2, "35.00", domain.UnitPiece},
+		{"COKE 2 x 35.00", true, "COKE", 2, "35.00", domain.UnitPiece},
+		{"0.512 kg @ 89.00", true, "", 0.512, "89.00", domain.UnitKg},
+		{"1.25KG X 120.00/KG", true, "", 1.25, "120.00", domain.UnitKg},
+		{"กล้วย 1.5 กก. @ 40", true, "กล้วย", 1.5, "40.00", domain.UnitKg},
+		{"500g @ 0.10", true, "", 0.5, "100.00", domain.UnitKg},
+		{"330 ml x 0.05", true, "", 0.33, "50.00", domain.UnitLitre},
+		{"2 ลิตร @ 1,200.00", true, "", 2, "1200.00", domain.UnitLitre},
+		{"TOWEL 3x4", false, "TOWEL 3x4", 0, "", ""},
+		{"COKE 2 x 35", false, "COKE 2 x 35", 0, "", ""},
+		{"0 @ 35.00", false, "0 @ 35.00", 0, "", ""},
+	}
+	for _, tt := range tests {
+		rest, q, ok := SplitQuantity(tt.line)
+		if ok != tt.ok || rest != tt.rest {
+			t.Errorf("SplitQuantity(%q) = %q, %v, want %q, %v", tt.line, rest, ok, tt.rest, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if math.Abs(q.Qty-tt.qty) > 1e-9 || q.Unit != tt.unit || !sameAmount(q.UnitPrice, amount(t, tt.unitPrice)) {
+			t.Errorf("SplitQuantity(%q) = %v %s @ %s, want %v %s @ %s", tt.line, q.Qty, q.Unit, q.UnitPrice, tt.qty, tt.unit, tt.unitPrice)
+		}
+	}
+}
+
+func TestFinishItems(t *testing.T) {
+	item := func(title, price string, qty float64, unitPrice, unit string) domain.TransactionItem {
+		it := domain.TransactionItem{Title: title, Category: "food", Quantity: qty, Unit: unit}
+		if price != "" {
+			it.Price = amount(t, price)
+		}
+		if unitPrice != "" {
+			it.UnitPrice = amount(t, unitPrice)
+		}
+		return it
+	}
+	tests := []struct {
+		name string
+		in   []domain.TransactionItem
+		want []domain.TransactionItem
+	}{
+		{
+			name: "consistent",
+			in:   []domain.TransactionItem{item("coffee", "90", 2, "45", "pcs")},
+			want: []domain.TransactionItem{item("coffee", "90", 2, "45", "pcs")},
+		},
+		{
+			name: "no quantity",
+			in:   []domain.TransactionItem{item("coffee", "45", 0, "", "")},
+			want: []domain.TransactionItem{item("coffee", "45", 1, "45", "pcs")},
+		},
+		{
+			name: "no price",
+			in:   []domain.TransactionItem{item("coffee", "", 3, "45", "pcs")},
+			want: []domain.TransactionItem{item("coffee", "135", 3, "45", "pcs")},
+		},
+		{
+			name: "unit price given as line price",
+			in:   []domain.TransactionItem{item("coffee", "45", 2, "45", "pcs")},
+			want: []domain.TransactionItem{item("coffee", "90", 2, "45", "pcs")},
+		},
+		{
+			name: "line price wins",
+			in:   []domain.TransactionItem{item("coffee", "45", 3, "10", "pcs")},
+			want: []domain.TransactionItem{item("coffee", "45", 3, "15", "pcs")},
+		},
+		{
+			name: "grams",
+			in:   []domain.TransactionItem{item("pork", "50", 500, "0.10", "g")},
+			want: []domain.TransactionItem{item("pork", "50", 0.5, "100", "kg")},
+		},
+		{
+			name: "millilitres",
+			in:   []domain.TransactionItem{item("milk", "33", 330, "0.10", "ml")},
+			want: []domain.TransactionItem{item("milk", "33", 0.33, "100", "L")},
+		},
+		{
+			name: "duplicates collapsed",
+			in:   []domain.TransactionItem{item("coke", "20", 1, "20", "pcs"), item("coke", "20", 1, "20", "pcs"), item("chips", "30", 1, "30", "pcs")},
+			want: []domain.TransactionItem{item("coke", "40", 2, "20", "pcs"), item("chips", "30", 1, "30", "pcs")},
+		},
+		{
+			name: "different unit prices kept",
+			in:   []domain.TransactionItem{item("coke", "20", 1, "20", "pcs"), item("coke", "25", 1, "25", "pcs")},
+			want: []domain.TransactionItem{item("coke", "20", 1, "20", "pcs"), item("coke", "25", 1, "25", "pcs")},
+		},
+	}
+	for _, tt := range tests {
+		got := FinishItems(tt.in)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d items, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i, g := range got {
+			w := tt.want[i]
+			if g.Title != w.Title || g.Unit != w.Unit || math.Abs(g.Quantity-w.Quantity) > 1e-9 ||
+				!sameAmount(g.Price, w.Price) || !sameAmount(g.UnitPrice, w.UnitPrice) {
+				t.Errorf("%s: item %d = %+v, want %+v", tt.name, i, g, w)
+			}
+		}
+	}
+}
+
+func TestFinishItemsDefaultCategory(t *testing.T) {
+	got := FinishItems([]domain.TransactionItem{{Title: "coffee", Price: domain.Amount{Minor: 45}}})
+	if got[0].Category != DefaultCategory {
+		t.Errorf("category = %q, want %q", got[0].Category, DefaultCategory)
+	}
+}
@@ -0,0 +1,119 @@
+// Package llm holds what every LLM backend shares: OCR text preprocessing,
+// the extraction prompt, the Transaction JSON schema and the decode, repair
+// and re-prompt loop. Backends only implement the transport (Generator).
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"os"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+// jsonRecovery counts how each transaction JSON decode attempt ended
+// (initial/reprompt x strict_ok/repaired_ok/failed), exported via expvar.
+var jsonRecovery = expvar.NewMap("llm_json_recovery")
+
+// DefaultCategory is used for items the model left without a category.
+const DefaultCategory = "อื่นๆ"
+
+// InvalidOutputError means the model answered but not with a usable transaction.
+type InvalidOutputError struct {
+	Raw string
+	Err error
+}
+
+func (e InvalidOutputError) Error() string {
+	return "invalid transaction JSON from model: " + e.Err.Error()
+}
+func (e InvalidOutputError) Unwrap() error        { return e.Err }
+func (e InvalidOutputError) Is(target error) bool { return target == domain.ErrInvalidOutput }
+
+// Generator sends one prompt to a backend and returns the raw text answer.
+type Generator func(ctx context.Context, prompt string) (string, error)
+
+// ParseTransaction generates a transaction for prompt and decodes it. Output
+// that is not valid JSON goes through the repair pass first; if that fails
+// too the model is re-prompted with its broken output and the parse error,
+// at most maxReprompts times. With fixMismatch, a transaction whose items
+// do not add up to the receipt total gets one more targeted re-prompt.
+func ParseTransaction(ctx context.Context, gen Generator, prompt string, maxReprompts int, fixMismatch bool) (*domain.Transaction, error) {
+	tr, err := generateJSON[domain.Transaction](ctx, gen, prompt, maxReprompts)
+	if err != nil {
+		return nil, err
+	}
+
+	tr.Items = FinishItems(tr.Items)
+	normalizeReceipt(tr)
+	if fixMismatch {
+		tr = reconcile(ctx, gen, prompt, tr)
+	}
+	return tr, nil
+}
+
+// generateJSON runs prompt and decodes the answer into a T, re-prompting at
+// most maxReprompts times while it does not decode.
+func generateJSON[T any](ctx context.Context, gen Generator, prompt string, maxReprompts int) (*T, error) {
+	raw, err := gen(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodeJSON[T](raw, 0)
+
+	// bounded re-prompt: show the model its broken output and the parse error
+	for attempt := 1; err != nil && attempt <= maxReprompts; attempt++ {
+		if raw, err = gen(ctx, BuildRepromptPrompt(prompt, raw, err)); err != nil {
+			return nil, err
+		}
+		v, err = decodeJSON[T](raw, attempt)
+	}
+	if err != nil {
+		jsonRecovery.Add("unrecoverable", 1)
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeTransaction parses the model's raw answer into a
+// domain.Transaction, strictly first and then through the repair pass.
+func decodeTransaction(raw string, attempt int) (*domain.Transaction, error) {
+	return decodeJSON[domain.Transaction](raw, attempt)
+}
+
+// decodeJSON parses the model's raw answer into a T, strictly first and
+// then through the repair pass. Every attempt is counted in jsonRecovery by
+// attempt kind and outcome.
+func decodeJSON[T any](raw string, attempt int) (*T, error) {
+	kind := "initial"
+	if attempt > 0 {
+		kind = "reprompt"
+	}
+	jsonRecovery.Add(kind+"_attempts", 1)
+
+	var v T
+	strictErr := json.Unmarshal([]byte(raw), &v)
+	if strictErr == nil {
+		jsonRecovery.Add(kind+"_strict_ok", 1)
+		return &v, nil
+	}
+
+	var repaired T
+	if err := decodeLenient(repairJSON(raw), &repaired); err == nil {
+		jsonRecovery.Add(kind+"_repaired_ok", 1)
+		logger.Warn().Err(strictErr).Int("attempt", attempt).Msg("repaired invalid JSON from model")
+		return &repaired, nil
+	}
+
+	jsonRecovery.Add(kind+"_failed", 1)
+	logger.Error().
+		Err(strictErr).
+		Int("attempt", attempt).
+		Str("raw_text", raw).
+		Msg("failed to unmarshal JSON from model")
+	return nil, InvalidOutputError{Raw: raw, Err: strictErr}
+}
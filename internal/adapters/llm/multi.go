@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// entriesAnswer is what the model writes for a message listing several
+// expenses.
+type entriesAnswer struct {
+	Transactions []entryAnswer `json:"transactions"`
+}
+
+type entryAnswer struct {
+	// Text is the part of the message the transaction was read from; its
+	// date is resolved from it afterwards.
+	Text     string                   `json:"text"`
+	Title    string                   `json:"title"`
+	Date     string                   `json:"date"`
+	Currency string                   `json:"currency"`
+	Items    []domain.TransactionItem `json:"items"`
+}
+
+// TransactionsSchema is the JSON Schema of the answer to BuildEntriesPrompt,
+// with the same category and unit restrictions as TransactionSchema.
+func TransactionsSchema(categories []string) map[string]any {
+	schema := schemaFor(reflect.TypeOf(entriesAnswer{}))
+	entry := schema["properties"].(map[string]any)["transactions"].(map[string]any)["items"].(map[string]any)
+	item := entry["properties"].(map[string]any)["items"].(map[string]any)["items"].(map[string]any)
+	item["properties"].(map[string]any)["unit"] = map[string]any{
+		"type": "string",
+		"enum": domain.Units,
+	}
+	if len(categories) > 0 {
+		item["properties"].(map[string]any)["category"] = map[string]any{
+			"type": "string",
+			"enum": categories,
+		}
+	}
+	return schema
+}
+
+// BuildEntriesPrompt builds the prompt that splits a chat message into
+// separate transactions.
+func BuildEntriesPrompt(message string, categories []string) string {
+	return fmt.Sprintf(
+		`Return only minified JSON in one line. No comments. No markdown.
+
+The MESSAGE was typed by a user and may list several separate expenses, in Thai or English.
+
+RULES:
+- Every separate expense is its own transaction in transactions[].
+- Things bought together at one place ("7-11: water 10, bread 25") are ONE transaction with several items.
+- text is the exact part of the MESSAGE the transaction comes from. When a date or day word ("yesterday", "เมื่อวาน", "last Friday", "15/03") applies to several expenses, copy it into the text of each of them.
+- title is a short name for the transaction: the shop, or the item when there is only one.
+- date is ISO-8601 (YYYY-MM-DD) only when the MESSAGE writes an absolute date for it. Otherwise "". Never compute dates from words like "yesterday".
+- Categories MUST be exactly one of: %v. Never invent new categories.
+- Every item MUST contain all six fields: title, price, category, quantity, unit_price, unit.
+- price is the total paid for the item. "coffee 2x45" means quantity 2, unit_price 45, price 90.
+- Without a quantity: quantity 1, unit "pcs", unit_price equal to price.
+- unit MUST be "pcs", "kg" or "L".
+- currency: ISO 4217 code, "THB" for ฿ or บาท or when no currency is written.
+- Words that are not expenses (greetings, notes) are ignored. Return {"transactions":[]} if there are none.
+
+OUTPUT JSON SCHEMA:
+{"transactions":[{"text":string,"title":string,"date":string,"currency":string,"items":[{"title":string,"price":number,"category":string,"quantity":number,"unit_price":number,"unit":string}]}]}
+
+MESSAGE:
+%s`,
+		categories,
+		message,
+	)
+}
+
+// ParseTransactions generates and decodes the transactions for a prompt
+// from BuildEntriesPrompt, repairing and re-prompting like ParseTransaction.
+// Entries without items are dropped.
+func ParseTransactions(ctx context.Context, gen Generator, prompt string, maxReprompts int) ([]domain.Entry, error) {
+	ans, err := generateJSON[entriesAnswer](ctx, gen, prompt, maxReprompts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.Entry, 0, len(ans.Transactions))
+	for _, e := range ans.Transactions {
+		if len(e.Items) == 0 {
+			continue
+		}
+		tr := &domain.Transaction{
+			Title:    strings.TrimSpace(e.Title),
+			Date:     e.Date,
+			Currency: e.Currency,
+			Items:    FinishItems(e.Items),
+		}
+		if tr.Title == "" {
+			tr.Title = tr.Items[0].Title
+		}
+		entries = append(entries, domain.Entry{Text: strings.TrimSpace(e.Text), Transaction: tr})
+	}
+	return entries, nil
+}
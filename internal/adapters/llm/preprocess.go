@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+)
+
+func CleanOCR(raw string) string {
+	s := raw
+
+	// remove ASCII table chars
+	s = regexp.MustCompile(`[|]+`).ReplaceAllString(s, " ")
+	s = regexp.MustCompile(`[-_=]{2,}`).ReplaceAllString(s, " ")
+
+	// remove unicode box drawing
+	s = regexp.MustCompile(`[│─┼┌┐└┘╔╗╚╝═]+`).ReplaceAllString(s, " ")
+
+	// collapse multiple newlines
+	s = regexp.MustCompile(`\n{2,}`).ReplaceAllString(s, "\n")
+
+	s = strings.TrimSpace(s)
+
+	// normalize spaces
+	s = regexp.MustCompile(`\s{2,}`).ReplaceAllString(s, " ")
+
+	return s
+}
+
+func MergeQtyLines(s string) string {
+	lines := strings.Split(s, "\n")
+
+	// "2@35.00", and weighed items such as "0.512 kg @ 89.00" or "1.25KG X 120.00"
+	qtyRe := regexp.MustCompile(`(?i)^\s*\d+(\.\d+)?\s*(` + unitPattern + `)?\s*(@\s*\d+(\.\d+)?|[x×]\s*\d+\.\d+)`)
+
+	var out []string
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if qtyRe.MatchString(line) && len(out) > 0 {
+			out[len(out)-1] = out[len(out)-1] + " " + line
+			continue
+		}
+
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func FixThaiOCR(s string) string {
+	for bad, good := range thaiFix {
+		s = strings.ReplaceAll(s, bad, good)
+	}
+	return s
+}
+
+func NormalizeNumbers(s string) string {
+	// remove spaces inside numbers: 1,     000 -> 1,000
+	re := regexp.MustCompile(`(\d)[,\s]+(\d{3})`)
+	return re.ReplaceAllString(s, `$1$2`)
+}
+
+func PreprocessOCR(raw string) string {
+	logger.Info().Str("raw_ocr", raw).Msg("raw OCR text")
+	return preprocess(raw)
+}
+
+// preprocess is PreprocessOCR without the logging, for cache keys.
+func preprocess(raw string) string {
+	s := CleanOCR(raw)
+
+	// 1. แก้เลขก่อน เพราะมีผลต่อ parsing ทั้งหมด
+	s = NormalizeNumbers(s)
+	s = SplitMergedPrices(s)
+
+	// 2. จัดโครงสร้างบรรทัด
+	s = MergeQtyLines(s)
+
+	// 3. แก้คำ OCR ไทย
+	s = FixThaiOCR(s)
+
+	return s
+}
+
+func SplitMergedPrices(s string) string {
+	re := regexp.MustCompile(`(\d+\.\d{2})(\d+\.\d{2})`)
+	return re.ReplaceAllString(s, `$1 $2`)
+}
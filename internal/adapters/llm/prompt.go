@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+)
+
+// PromptVersion identifies the prompt wording; bump it whenever the prompt
+// changes in a way that alters model output.
+const PromptVersion = "v8"
+
+// CacheKey identifies a transaction extraction result: same preprocessed
+// text, categories, model, prompt version and backend settings (see
+// Settings) give the same answer.
+func CacheKey(model, settings, text string, categories []string) string {
+	return cache.Key("tx", PromptVersion, model, settings, preprocess(text), strings.Join(categories, "\x1f"))
+}
+
+// Settings fingerprints the backend options that change a result: the
+// structured output format, the re-prompt budget and the mismatch fix.
+func Settings(format string, maxReprompts int, fixMismatch bool) string {
+	return fmt.Sprintf("format=%s reprompts=%d fix_mismatch=%t", format, maxReprompts, fixMismatch)
+}
+
+// BuildPrompt builds the receipt extraction prompt shared by all backends.
+func BuildPrompt(ocrText string, categories []string) string {
+	return fmt.Sprintf(
+		`Return only minified JSON in one line. No comments. No markdown.
+
+CRITICAL RULES:
+- Categories MUST be exactly one of: %v. Never invent new categories.
+- category is REQUIRED for every item.
+- NEVER omit category.
+- If unsure, use the closest match from the categories list.
+- Every item MUST contain all six fields: title, price, category, quantity, unit_price, unit.
+- Only real purchased products may appear in items[].
+- NEVER include store name, branch, receipt header, tax id, POS id, totals, VAT, CASH, Change, discount lines, or thank-you text in items[].
+- Any token where numbers touch letters (example: "470X", "3S") is a PRODUCT CODE, NOT a price.
+- A price MUST be a standalone decimal number at the END of a product line.
+- Lines containing quantity/unit patterns such as "@", "PCS", "หน่วย" are NOT products.
+- Any line starting with a number followed by "@" is NEVER a product.
+- Quantity/unit lines belong to the previous product and must be merged into that product.
+- price is the LINE TOTAL paid for the item. "2@35.00" means quantity 2, unit_price 35.00, price 70.00.
+- Weighed items ("0.512 kg @ 89.00"): quantity 0.512, unit "kg", unit_price 89.00 per kg. Liquids sold by volume use unit "L".
+- unit MUST be "pcs", "kg" or "L". Convert grams to kg and ml to L.
+- Without a quantity line: quantity 1, unit "pcs", unit_price equal to price.
+- The same product printed on several consecutive lines is ONE item with the summed quantity and price.
+- Prefer including uncertain items rather than dropping them unless clearly a header/total.
+- Remove prefixes like "1P", "2P", "A#", "P#", "A ", "P ".
+- Titles must be short product names only.
+- date MUST be ISO-8601. Include time if present: YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS(+TZ)
+- Thai receipts print Buddhist Era years (2567, or 67 in 15/03/67). Convert them: CE year = BE year - 543 (2567 -> 2024).
+- If a line appears to be a product but is messy OCR, still include it.
+- Normalise every amount to a plain decimal number, without thousands separators: "1,234.50" is 1234.50 and "1.234,50" is 1234.50.
+- Only drop lines from items[] that are clearly totals, VAT, CASH, Change, receipt numbers, or discounts.
+- If price is unclear, infer from nearest decimal number.
+
+RECEIPT FIELDS (outside items[]):
+- currency: ISO 4217 code of the amounts: "THB" for ฿ or บาท, "USD" for $, "JPY" for ¥, "MYR" for RM. "THB" if not shown.
+- subtotal: amount before discount, service charge and VAT, if printed. Otherwise null.
+- discount_total: sum of all discounts as a POSITIVE number, if any. Otherwise null.
+- service_charge: service charge amount, if printed. Otherwise null.
+- vat_amount: VAT amount (ภาษีมูลค่าเพิ่ม), if printed. Otherwise null.
+- vat_rate: VAT percent as a number, e.g. 7 for "VAT 7%%". Otherwise null.
+- grand_total: the final amount to pay (Total, Grand Total, ยอดสุทธิ, รวมทั้งสิ้น). Otherwise null.
+- payment_method: "cash", "card", "qr" (PromptPay / QR), "e_wallet" (TrueMoney, Rabbit LINE Pay, ShopeePay) or "" if not shown.
+- merchant_tax_id: the seller's tax ID (TAX ID, เลขประจำตัวผู้เสียภาษี) digits only, or "".
+- receipt_number: the receipt / invoice / bill number (Receipt No, เลขที่), or "".
+- Never put CASH received or Change amounts in any field.
+
+OUTPUT JSON SCHEMA:
+{"title":string,"date":string,"items":[{"title":string,"price":number,"category":string,"quantity":number,"unit_price":number,"unit":string}],"currency":string,"subtotal":number|null,"discount_total":number|null,"service_charge":number|null,"vat_amount":number|null,"vat_rate":number|null,"grand_total":number|null,"payment_method":string,"merchant_tax_id":string,"receipt_number":string}
+
+OCR TEXT:
+%s`,
+		categories,
+		ocrText,
+	)
+}
+
+// BuildRepromptPrompt asks the model to fix its own output. The broken
+// output is capped so a runaway answer cannot blow up the context.
+func BuildRepromptPrompt(prompt, broken string, parseErr error) string {
+	const maxBroken = 4000
+	if len(broken) > maxBroken {
+		// back off to a rune boundary so a Thai character is not cut in half
+		cut := maxBroken
+		for cut > 0 && !utf8.RuneStart(broken[cut]) {
+			cut--
+		}
+		broken = broken[:cut] + "...(truncated)"
+	}
+	return fmt.Sprintf(`%s
+
+YOUR PREVIOUS OUTPUT WAS INVALID:
+%s
+
+PARSE ERROR: %v
+
+Return the complete corrected JSON only, in one line, matching the schema exactly.
+Prices MUST be plain numbers (no quotes, no thousands separators).
+If the output was cut off, shorten item titles so everything fits.`,
+		prompt, broken, parseErr)
+}
@@ -0,0 +1,25 @@
+package llm
+
+import "testing"
+
+func TestCacheKeyCoversSettings(t *testing.T) {
+	cats := []string{"อาหาร", "อื่นๆ"}
+	base := CacheKey("model", Settings("schema", 1, false), "COKE 35.00", cats)
+	tests := []struct {
+		name string
+		key  string
+		same bool
+	}{
+		{"same inputs", CacheKey("model", Settings("schema", 1, false), "COKE 35.00", cats), true},
+		{"mismatch fix on", CacheKey("model", Settings("schema", 1, true), "COKE 35.00", cats), false},
+		{"more re-prompts", CacheKey("model", Settings("schema", 2, false), "COKE 35.00", cats), false},
+		{"other format", CacheKey("model", Settings("json", 1, false), "COKE 35.00", cats), false},
+		{"other model", CacheKey("model2", Settings("schema", 1, false), "COKE 35.00", cats), false},
+		{"other categories", CacheKey("model", Settings("schema", 1, false), "COKE 35.00", cats[:1]), false},
+	}
+	for _, tt := range tests {
+		if (tt.key == base) != tt.same {
+			t.Errorf("%s: key equal = %v, want %v", tt.name, tt.key == base, tt.same)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// paymentAliases maps what models (and receipts) call a payment method to
+// one of domain.PaymentMethods.
+var paymentAliases = map[string]string{
+	"cash": domain.PaymentCash, "เงินสด": domain.PaymentCash,
+	"card": domain.PaymentCard, "credit": domain.PaymentCard, "credit card": domain.PaymentCard,
+	"debit": domain.PaymentCard, "debit card": domain.PaymentCard, "visa": domain.PaymentCard,
+	"mastercard": domain.PaymentCard, "บัตรเครดิต": domain.PaymentCard,
+	"qr": domain.PaymentQR, "qr code": domain.PaymentQR, "promptpay": domain.PaymentQR,
+	"thai qr": domain.PaymentQR, "พร้อมเพย์": domain.PaymentQR,
+	"e_wallet": domain.PaymentEWallet, "e-wallet": domain.PaymentEWallet, "ewallet": domain.PaymentEWallet,
+	"wallet": domain.PaymentEWallet, "truemoney": domain.PaymentEWallet, "rabbit line pay": domain.PaymentEWallet,
+	"line pay": domain.PaymentEWallet, "shopeepay": domain.PaymentEWallet,
+}
+
+var nonDigitRe = regexp.MustCompile(`\D`)
+
+// normalizeReceipt cleans up the receipt-level fields the model filled in:
+// known payment method names, a digits-only 13 digit tax ID, discounts as
+// positive amounts and VAT rates as percent.
+func normalizeReceipt(tr *domain.Transaction) {
+	tr.PaymentMethod = paymentAliases[strings.ToLower(strings.TrimSpace(tr.PaymentMethod))]
+
+	tr.MerchantTaxID = strings.TrimSpace(tr.MerchantTaxID)
+	if id := nonDigitRe.ReplaceAllString(tr.MerchantTaxID, ""); len(id) == 13 {
+		tr.MerchantTaxID = id
+	}
+	tr.ReceiptNumber = strings.TrimSpace(tr.ReceiptNumber)
+
+	if tr.DiscountTotal != nil {
+		d := tr.DiscountTotal.Abs()
+		tr.DiscountTotal = &d
+	}
+	if tr.VATRate != nil && *tr.VATRate > 0 && *tr.VATRate < 1 {
+		r := math.Round(*tr.VATRate*10000) / 100 // 0.07 -> 7
+		tr.VATRate = &r
+	}
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// mismatchFixes counts the re-prompts sent for transactions whose items do
+// not add up ("attempts") and how they ended ("fixed", "improved", "kept").
+var mismatchFixes = expvar.NewMap("llm_mismatch_fix")
+
+// reconcile re-prompts the model once with the arithmetic mismatch and the
+// suspected items. The answer's items replace tr's only when they are
+// closer to tr's receipt total; any failure keeps tr.
+func reconcile(ctx context.Context, gen Generator, prompt string, tr *domain.Transaction) *domain.Transaction {
+	rec := domain.Reconcile(tr)
+	if rec == nil || rec.Consistent {
+		return tr
+	}
+	mismatchFixes.Add("attempts", 1)
+
+	raw, err := gen(ctx, BuildMismatchPrompt(prompt, tr, rec))
+	if err != nil {
+		logger.Warn().Err(err).Msg("mismatch re-prompt failed")
+		mismatchFixes.Add("kept", 1)
+		return tr
+	}
+	fixed, err := decodeTransaction(raw, 1)
+	if err != nil {
+		mismatchFixes.Add("kept", 1)
+		return tr
+	}
+	// only the items are taken: judged against totals the model may have
+	// changed, a "fix" could just move the total
+	candidate := tr.Clone()
+	candidate.Items = FinishItems(fixed.Items)
+
+	after := domain.Reconcile(candidate)
+	switch {
+	case after.Consistent:
+		mismatchFixes.Add("fixed", 1)
+	case after.Discrepancy.Abs().Cmp(rec.Discrepancy.Abs()) < 0:
+		mismatchFixes.Add("improved", 1)
+	default:
+		mismatchFixes.Add("kept", 1)
+		return tr
+	}
+	logger.Info().Str("before", rec.String()).Str("after", after.String()).Msg("mismatch re-prompt accepted")
+	return candidate
+}
+
+// BuildMismatchPrompt asks the model to re-check a transaction whose items
+// do not add up to the receipt total.
+func BuildMismatchPrompt(prompt string, tr *domain.Transaction, rec *domain.Reconciliation) string {
+	out, _ := json.Marshal(tr)
+
+	var hints strings.Builder
+	for _, s := range rec.Suspects {
+		item := tr.Items[s.Index]
+		switch s.Reason {
+		case domain.SuspectExceedsTotal:
+			fmt.Fprintf(&hints, "- %q (%s) costs more than the whole receipt; it is probably a product code, not a price.\n", item.Title, item.Price)
+		case domain.SuspectMatchesDiscrepancy:
+			fmt.Fprintf(&hints, "- %q (%s) equals the difference; check it is a real product and not a total, discount or duplicate.\n", item.Title, item.Price)
+		case domain.SuspectQuantity:
+			fmt.Fprintf(&hints, "- %q (%s): the difference is a multiple of its price; check its quantity line.\n", item.Title, item.Price)
+		}
+	}
+	if hints.Len() == 0 {
+		hints.WriteString("- An item may have been dropped; look for product lines that are missing from items[].\n")
+	}
+
+	return fmt.Sprintf(`%s
+
+YOUR PREVIOUS OUTPUT:
+%s
+
+THE ITEMS DO NOT ADD UP: %s.
+%s
+Re-read the OCR text and return the complete corrected JSON only, in one line, matching the schema exactly.
+Each item price is the line total (quantity x unit price). Do not invent items or change totals to make the sum match.`,
+		prompt, out, rec, hints.String())
+}
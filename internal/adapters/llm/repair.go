@@ -0,0 +1,174 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// repairJSON fixes the mistakes the model makes most often: markdown fences
+// or chatter around the object, trailing commas, and output truncated by
+// num_predict. Truncated output is cut back to the last complete value
+// (dropping a half-written item) and the open brackets are closed.
+func repairJSON(raw string) string {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimSuffix(s, "```")
+	if i := strings.IndexByte(s, '{'); i > 0 {
+		s = s[i:]
+	}
+
+	var (
+		out      bytes.Buffer
+		stack    []byte // open brackets
+		inString bool
+		escaped  bool
+
+		// last point where the output can be cut and closed cleanly
+		safeLen   int
+		safeStack []byte
+	)
+	markSafe := func() {
+		safeLen = out.Len()
+		safeStack = append(safeStack[:0], stack...)
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			trimTrailingComma(&out)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			out.WriteByte(c)
+			if len(stack) == 0 {
+				// complete top-level object, ignore anything after it
+				return out.String()
+			}
+			markSafe()
+			continue
+		case ',':
+			// a comma inside a nested object is not a cut point: cutting
+			// there would keep a half-written item
+			if len(stack) == 1 || (len(stack) > 1 && stack[len(stack)-1] == '[') {
+				markSafe()
+			}
+		}
+		out.WriteByte(c)
+	}
+
+	if len(stack) == 0 {
+		return out.String()
+	}
+
+	// truncated: roll back to the last complete value and close what is open
+	b := out.Bytes()[:safeLen]
+	b = bytes.TrimRight(b, " \t\r\n,")
+	res := bytes.NewBuffer(b)
+	for i := len(safeStack) - 1; i >= 0; i-- {
+		if safeStack[i] == '{' {
+			res.WriteByte('}')
+		} else {
+			res.WriteByte(']')
+		}
+	}
+	return res.String()
+}
+
+func trimTrailingComma(out *bytes.Buffer) {
+	b := bytes.TrimRight(out.Bytes(), " \t\r\n")
+	if len(b) > 0 && b[len(b)-1] == ',' {
+		out.Truncate(len(b) - 1)
+	}
+}
+
+// decodeLenient decodes raw into out (a pointer), turning string numbers into
+// numbers where out's type expects them.
+func decodeLenient(raw string, out any) error {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	v = coerceNumbers(v, reflect.TypeOf(out).Elem())
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// coerceNumbers walks v alongside the Go type it will be decoded into and
+// turns strings into numbers where a number is expected.
+func coerceNumbers(v any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int64:
+		if s, ok := v.(string); ok {
+			if n, err := parseLooseNumber(s); err == nil {
+				return json.Number(strconv.FormatFloat(n, 'f', -1, 64))
+			}
+		}
+	case reflect.Slice:
+		if arr, ok := v.([]any); ok {
+			for i := range arr {
+				arr[i] = coerceNumbers(arr[i], t.Elem())
+			}
+		}
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if fv, ok := obj[name]; ok {
+				obj[name] = coerceNumbers(fv, f.Type)
+			}
+		}
+	}
+	return v
+}
+
+// parseLooseNumber reads numbers written as strings: "1,250.00",
+// "1.250,00", "฿85", "85 บาท". domain.Amount fields parse their own.
+func parseLooseNumber(s string) (float64, error) {
+	a, err := domain.ParseAmount(s)
+	if err != nil {
+		return 0, err
+	}
+	return a.Float64(), nil
+}
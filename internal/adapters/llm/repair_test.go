@@ -0,0 +1,198 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "valid",
+			raw:  `{"title":"7-11","items":[]}`,
+			want: `{"title":"7-11","items":[]}`,
+		},
+		{
+			name: "fenced block",
+			raw:  "```json\n{\"title\":\"7-11\"}\n```",
+			want: `{"title":"7-11"}`,
+		},
+		{
+			name: "chatter around the object",
+			raw:  `Here is the JSON: {"title":"7-11"} Hope this helps!`,
+			want: `{"title":"7-11"}`,
+		},
+		{
+			name: "trailing commas",
+			raw:  `{"items":[{"title":"Coke","price":35,},],}`,
+			want: `{"items":[{"title":"Coke","price":35}]}`,
+		},
+		{
+			name: "braces inside strings",
+			raw:  `{"title":"a {b} [c] \"d\"","items":[]}`,
+			want: `{"title":"a {b} [c] \"d\"","items":[]}`,
+		},
+		{
+			name: "truncated inside an item",
+			raw:  `{"title":"7-11","items":[{"title":"Coke","price":35},{"title":"Wa`,
+			want: `{"title":"7-11","items":[{"title":"Coke","price":35}]}`,
+		},
+		{
+			name: "truncated after a complete item",
+			raw:  `{"title":"7-11","items":[{"title":"Coke","price":35},`,
+			want: `{"title":"7-11","items":[{"title":"Coke","price":35}]}`,
+		},
+		{
+			name: "no object at all",
+			raw:  `Sorry, I cannot read this receipt.`,
+			want: `Sorry, I cannot read this receipt.`,
+		},
+		{
+			name: "truncated in a top-level string",
+			raw:  `{"title":"7-11","date":"2024-03`,
+			want: `{"title":"7-11"}`,
+		},
+	}
+	for _, tt := range tests {
+		if got := repairJSON(tt.raw); got != tt.want {
+			t.Errorf("%s: repairJSON(%q) = %q, want %q", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeLenient(t *testing.T) {
+	raw := `{"title":"Tops","items":[{"title":"Rice","price":"1,250.00","quantity":"2","unit_price":"625"}],"vat_rate":"7","grand_total":"฿1,250.00"}`
+	var tr domain.Transaction
+	if err := decodeLenient(raw, &tr); err != nil {
+		t.Fatalf("decodeLenient: %v", err)
+	}
+	if len(tr.Items) != 1 {
+		t.Fatalf("items = %+v, want 1", tr.Items)
+	}
+	item := tr.Items[0]
+	if item.Price.String() != "1250.00" || item.Quantity != 2 || item.UnitPrice.String() != "625" {
+		t.Errorf("item = price %s, quantity %v, unit price %s; want 1250.00, 2, 625", item.Price, item.Quantity, item.UnitPrice)
+	}
+	if tr.VATRate == nil || *tr.VATRate != 7 {
+		t.Errorf("vat_rate = %v, want 7", tr.VATRate)
+	}
+	if tr.GrandTotal == nil || tr.GrandTotal.String() != "1250.00" {
+		t.Errorf("grand_total = %v, want 1250.00", tr.GrandTotal)
+	}
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		items int
+		ok    bool
+	}{
+		{"strict", `{"title":"a","items":[{"title":"x","price":10}]}`, 1, true},
+		{"truncated", `{"title":"a","items":[{"title":"x","price":10},{"title":"y","pr`, 1, true},
+		{"string price", `{"title":"a","items":[{"title":"x","price":"10.50"}]}`, 1, true},
+		{"fenced with trailing comma", "```json\n{\"title\":\"a\",\"items\":[{\"title\":\"x\",\"price\":10},]}\n```", 1, true},
+		{"not JSON", `I could not read the receipt.`, 0, false},
+	}
+	for _, tt := range tests {
+		tr, err := decodeTransaction(tt.raw, 0)
+		if !tt.ok {
+			if !errors.Is(err, domain.ErrInvalidOutput) {
+				t.Errorf("%s: err = %v, want ErrInvalidOutput", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(tr.Items) != tt.items {
+			t.Errorf("%s: %d items, want %d", tt.name, len(tr.Items), tt.items)
+		}
+	}
+}
+
+// scripted answers prompts in order and records them.
+type scripted struct {
+	answers []string
+	prompts []string
+}
+
+func (s *scripted) gen(_ context.Context, prompt string) (string, error) {
+	s.prompts = append(s.prompts, prompt)
+	if len(s.answers) == 0 {
+		return "", errors.New("no more answers")
+	}
+	a := s.answers[0]
+	s.answers = s.answers[1:]
+	return a, nil
+}
+
+func TestGenerateJSONReprompts(t *testing.T) {
+	s := &scripted{answers: []string{
+		`Sorry, I cannot help with that.`,
+		`{"title":"fixed","items":[]}`,
+	}}
+	tr, err := generateJSON[domain.Transaction](context.Background(), s.gen, "PROMPT", 2)
+	if err != nil {
+		t.Fatalf("generateJSON: %v", err)
+	}
+	if tr.Title != "fixed" {
+		t.Errorf("title = %q, want fixed", tr.Title)
+	}
+	if len(s.prompts) != 2 {
+		t.Fatalf("%d prompts sent, want 2", len(s.prompts))
+	}
+	re := s.prompts[1]
+	if !strings.HasPrefix(re, "PROMPT") || !strings.Contains(re, "YOUR PREVIOUS OUTPUT WAS INVALID") ||
+		!strings.Contains(re, "Sorry, I cannot help with that.") {
+		t.Errorf("re-prompt does not show the prompt and the broken output:\n%s", re)
+	}
+}
+
+func TestGenerateJSONGivesUp(t *testing.T) {
+	s := &scripted{answers: []string{"nope", "still nope", "never"}}
+	_, err := generateJSON[domain.Transaction](context.Background(), s.gen, "PROMPT", 1)
+	if !errors.Is(err, domain.ErrInvalidOutput) {
+		t.Errorf("err = %v, want ErrInvalidOutput", err)
+	}
+	if len(s.prompts) != 2 {
+		t.Errorf("%d prompts sent, want 2 (one re-prompt)", len(s.prompts))
+	}
+}
+
+func TestGenerateJSONRepairsWithoutReprompt(t *testing.T) {
+	s := &scripted{answers: []string{`{"title":"a","items":[{"title":"x","price":10},`}}
+	if _, err := generateJSON[domain.Transaction](context.Background(), s.gen, "PROMPT", 2); err != nil {
+		t.Fatalf("generateJSON: %v", err)
+	}
+	if len(s.prompts) != 1 {
+		t.Errorf("%d prompts sent, want 1", len(s.prompts))
+	}
+}
+
+func TestBuildRepromptPromptTruncatesOnRuneBoundary(t *testing.T) {
+	// Thai letters are three bytes each; offset 4000 lands inside one
+	broken := strings.Repeat("ก", 2000)
+	re := BuildRepromptPrompt("PROMPT", broken, errors.New("bad"))
+	if !utf8.ValidString(re) {
+		t.Fatal("re-prompt is not valid UTF-8")
+	}
+	if !strings.Contains(re, "ก...(truncated)") || strings.Count(re, "ก") != 1333 {
+		t.Errorf("broken output cut at %d Thai letters, want 1333", strings.Count(re, "ก"))
+	}
+
+	short := "สวัสดี"
+	if re := BuildRepromptPrompt("PROMPT", short, errors.New("bad")); !strings.Contains(re, short+"\n") {
+		t.Errorf("short output changed:\n%s", re)
+	}
+}
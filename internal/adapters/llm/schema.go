@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// TransactionSchema returns the JSON Schema of domain.Transaction that is sent
+// as the backend's structured output format. Item categories are restricted to the
+// request's categories so the model cannot invent new ones, units to
+// domain.Units and the payment method to domain.PaymentMethods.
+func TransactionSchema(categories []string) map[string]any {
+	schema := schemaFor(reflect.TypeOf(domain.Transaction{}))
+	schema["properties"].(map[string]any)["payment_method"] = map[string]any{
+		"type": "string",
+		"enum": append(append([]string{}, domain.PaymentMethods...), ""),
+	}
+	item := schema["properties"].(map[string]any)["items"].(map[string]any)["items"].(map[string]any)
+	item["properties"].(map[string]any)["unit"] = map[string]any{
+		"type": "string",
+		"enum": domain.Units,
+	}
+	if len(categories) > 0 {
+		item["properties"].(map[string]any)["category"] = map[string]any{
+			"type": "string",
+			"enum": categories,
+		}
+	}
+	return schema
+}
+
+var amountType = reflect.TypeOf(domain.Amount{})
+
+// schemaFor derives a JSON Schema from a Go type using its json tags. Fields
+// tagged omitempty are optional, everything else is required, and no
+// additional properties are allowed. domain.Amount is a plain number.
+func schemaFor(t reflect.Type) map[string]any {
+	if t == amountType {
+		return map[string]any{"type": "number"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := schemaFor(t.Elem())
+		s["type"] = []any{s["type"], "null"}
+		return s
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaFor(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"encoding/json"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// ItemStream watches a transaction JSON object while the model is still
+// writing it and hands over every element of the top-level "items" array as
+// soon as its closing brace arrives. Elements that do not decode are
+// skipped; the final, fully parsed transaction stays authoritative.
+type ItemStream struct {
+	onItem func(domain.TransactionItem)
+
+	buf      []byte
+	stack    []byte // open containers, '{' or '['
+	inString bool
+	escaped  bool
+	strStart int
+
+	// top-level object keys, to find "items"
+	expectKey bool
+	lastKey   string
+
+	itemsDepth int // len(stack) inside the items array, 0 until found
+	itemStart  int
+}
+
+func NewItemStream(onItem func(domain.TransactionItem)) *ItemStream {
+	return &ItemStream{onItem: onItem, itemStart: -1}
+}
+
+// Write feeds the next generated chunk.
+func (p *ItemStream) Write(chunk string) {
+	start := len(p.buf)
+	p.buf = append(p.buf, chunk...)
+	for i := start; i < len(p.buf); i++ {
+		p.scan(i)
+	}
+}
+
+func (p *ItemStream) scan(i int) {
+	c := p.buf[i]
+	if p.inString {
+		switch {
+		case p.escaped:
+			p.escaped = false
+		case c == '\\':
+			p.escaped = true
+		case c == '"':
+			p.inString = false
+			if len(p.stack) == 1 && p.expectKey {
+				_ = json.Unmarshal(p.buf[p.strStart:i+1], &p.lastKey)
+			}
+		}
+		return
+	}
+
+	switch c {
+	case '"':
+		p.inString, p.strStart = true, i
+	case ':':
+		if len(p.stack) == 1 {
+			p.expectKey = false
+		}
+	case ',':
+		if len(p.stack) == 1 {
+			p.expectKey = true
+		}
+	case '{', '[':
+		p.stack = append(p.stack, c)
+		switch {
+		case len(p.stack) == 1:
+			p.expectKey = true
+		case c == '[' && len(p.stack) == 2 && p.lastKey == "items" && p.itemsDepth == 0:
+			p.itemsDepth = len(p.stack)
+		case c == '{' && p.itemsDepth > 0 && len(p.stack) == p.itemsDepth+1:
+			p.itemStart = i
+		}
+	case '}', ']':
+		if len(p.stack) == 0 {
+			return
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+		if c == '}' && p.itemStart >= 0 && len(p.stack) == p.itemsDepth {
+			p.emit(p.buf[p.itemStart : i+1])
+			p.itemStart = -1
+		}
+		if c == ']' && len(p.stack) == p.itemsDepth-1 {
+			p.itemsDepth = -1 // items array closed, ignore the rest
+		}
+	}
+}
+
+func (p *ItemStream) emit(raw []byte) {
+	var item domain.TransactionItem
+	if err := decodeLenient(string(raw), &item); err != nil {
+		logger.Warn().Err(err).Str("item", string(raw)).Msg("skipping undecodable streamed item")
+		return
+	}
+	fillDefaults(&item)
+	p.onItem(item)
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+func TestItemStream(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string // titles, in order
+	}{
+		{
+			name: "plain",
+			json: `{"title":"7-11","items":[{"title":"water","price":10},{"title":"bread","price":25}],"currency":"THB"}`,
+			want: []string{"water", "bread"},
+		},
+		{
+			name: "items after other arrays",
+			json: `{"tags":[{"title":"not an item"}],"meta":{"items":[{"title":"nested"}]},"items":[{"title":"coffee","price":45}]}`,
+			want: []string{"coffee"},
+		},
+		{
+			name: "braces and quotes in strings",
+			json: `{"title":"a \"b\" {c}","items":[{"title":"x}]{\"y","price":1},{"title":"z\\","price":2}]}`,
+			want: []string{`x}]{"y`, `z\`},
+		},
+		{
+			name: "objects after the items array",
+			json: "```json\n" + `{"items":[{"title":"tea","price":30}],"extra":[{"title":"late"}]}` + "\n```",
+			want: []string{"tea"},
+		},
+		{
+			name: "no items",
+			json: `{"title":"nothing","items":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		for _, size := range []int{1, 3, 7, len(tt.json)} {
+			var got []string
+			p := NewItemStream(func(item domain.TransactionItem) { got = append(got, item.Title) })
+			for i := 0; i < len(tt.json); i += size {
+				p.Write(tt.json[i:min(i+size, len(tt.json))])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s, chunks of %d: items %q, want %q", tt.name, size, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestItemStreamEmitsEarly(t *testing.T) {
+	var got []domain.TransactionItem
+	p := NewItemStream(func(item domain.TransactionItem) { got = append(got, item) })
+	p.Write(`{"items":[{"title":"coffee","price":90,"quantity":2}`)
+	if len(got) != 1 {
+		t.Fatalf("got %d items before the array closed, want 1", len(got))
+	}
+	if got[0].Unit != domain.UnitPiece || got[0].UnitPrice.Float64() != 45 || got[0].Category != DefaultCategory {
+		t.Errorf("streamed item %+v has no defaults filled in", got[0])
+	}
+	p.Write(`,{"title":"cake"`)
+	if len(got) != 1 {
+		t.Errorf("an unfinished item was emitted")
+	}
+}
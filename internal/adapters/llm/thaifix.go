@@ -1,4 +1,4 @@
-package ollama
+package llm
 
 var thaiFix = map[string]string{
 	"ถาวเหลือง": "ถั่วเหลือง",
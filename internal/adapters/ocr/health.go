@@ -0,0 +1,35 @@
+package ocr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Check implements ports.HealthPort by listing models on the Typhoon API,
+// which verifies both reachability and the API key.
+func (t *TyphoonOCR) Check(ctx context.Context, _ string) (bool, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.healthURL, nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return false, "typhoon unreachable: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, "typhoon reachable"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// throttled but up; requests are retried with backoff
+		return true, "typhoon reachable (rate limited)"
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return false, "typhoon rejected the API key"
+	default:
+		return false, fmt.Sprintf("typhoon returned %d", resp.StatusCode)
+	}
+}
@@ -10,22 +10,67 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/env"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/media"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/progress"
 )
 
-type RateLimitError struct{ Msg string }
-func (e RateLimitError) Error() string { return e.Msg }
+type RateLimitError struct {
+	Msg        string
+	RetryAfter time.Duration // from the Retry-After header, 0 if absent
+}
+
+func (e RateLimitError) Error() string             { return e.Msg }
+func (e RateLimitError) Is(target error) bool      { return target == domain.ErrRateLimited }
+func (e RateLimitError) RetryDelay() time.Duration { return e.RetryAfter }
 
 type ServerError struct{ Msg string }
-func (e ServerError) Error() string { return e.Msg }
+
+func (e ServerError) Error() string        { return e.Msg }
+func (e ServerError) Is(target error) bool { return target == domain.ErrUnavailable }
+
+// ConnectionError wraps transport failures (DNS, TLS, client timeout).
+type ConnectionError struct{ Err error }
+
+func (e ConnectionError) Error() string        { return "OCR API connection error: " + e.Err.Error() }
+func (e ConnectionError) Unwrap() error        { return e.Err }
+func (e ConnectionError) Is(target error) bool { return target == domain.ErrUnavailable }
+
+// APIError is any other non-200 answer from the OCR API.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e APIError) Error() string { return fmt.Sprintf("OCR API error: %d - %s", e.StatusCode, e.Body) }
+func (e APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return target == domain.ErrInvalidInput
+	}
+	return false
+}
+
+// InvalidField blames the upload: the OCR API only ever sees image_data.
+func (e APIError) InvalidField() string { return "image_data" }
 
 type TyphoonOCR struct {
 	apiKey     string
 	baseURL    string
+	healthURL  string
 	httpClient *http.Client
 	defaultOcr OcrParams
+	// maxPdfPages caps how many PDF pages are sent for OCR (0 = all pages).
+	maxPdfPages int
 }
 
 func NewTyphoonOCR() *TyphoonOCR {
@@ -35,8 +80,10 @@ func NewTyphoonOCR() *TyphoonOCR {
 	}
 
 	return &TyphoonOCR{
-		apiKey:  apiKey,
-		baseURL: "https://api.opentyphoon.ai/v1/ocr",
+		apiKey:      apiKey,
+		baseURL:     "https://api.opentyphoon.ai/v1/ocr",
+		healthURL:   "https://api.opentyphoon.ai/v1/models",
+		maxPdfPages: env.Int("OPENTYPHOON_PDF_MAX_PAGES", 10),
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 			Transport: &http.Transport{
@@ -56,6 +103,11 @@ func NewTyphoonOCR() *TyphoonOCR {
 	}
 }
 
+// Fingerprint implements ports.Fingerprinter.
+func (t *TyphoonOCR) Fingerprint() string {
+	return fmt.Sprintf("%+v max_pdf_pages=%d", t.defaultOcr, t.maxPdfPages)
+}
+
 func (t *TyphoonOCR) ExtractText(ctx context.Context, img []byte) (string, error) {
 
 	backoffs := []time.Duration{
@@ -64,26 +116,53 @@ func (t *TyphoonOCR) ExtractText(ctx context.Context, img []byte) (string, error
 		30 * time.Second,
 	}
 
+	params := t.defaultOcr
+	if f, _ := media.Sniff(img); f == media.PDF {
+		params.Pages = t.pdfPages()
+	}
+
 	for attempt := 0; attempt <= len(backoffs); attempt++ {
 
-		body, writer, err := buildMultipartRequest(img, t.defaultOcr)
+		body, writer, err := buildMultipartRequest(img, params)
 		if err != nil {
 			return "", err
 		}
 
 		raw, err := t.sendOcrRequest(ctx, body, writer)
 		if err == nil {
-			return parseOcrResponse(raw)
+			return parseOcrResponse(raw, params.Pages)
+		}
+
+		// a PDF shorter than the cap: shrink the page list to the real page
+		// count and send it again without spending a retry
+		if pages := retryPages(err, params.Pages, img); pages != nil {
+			log.Printf("PDF has %d pages, retrying with only those", len(pages))
+			params.Pages = pages
+			attempt--
+			continue
 		}
 
 		if attempt == len(backoffs) {
 			return "", err
 		}
 
-		switch err.(type) {
+		switch e := err.(type) {
 		case RateLimitError, ServerError:
 			wait := backoffs[attempt]
+			if rl, ok := e.(RateLimitError); ok && rl.RetryAfter > wait {
+				// longer than we are willing to hold the request: let the client retry later
+				if rl.RetryAfter > backoffs[len(backoffs)-1] {
+					return "", err
+				}
+				wait = rl.RetryAfter
+			}
 			log.Printf("OCR retry %d in %v", attempt+1, wait)
+			progress.Report(ctx, progress.Event{
+				Stage:   progress.OCRRetrying,
+				Message: err.Error(),
+				Attempt: attempt + 1,
+				RetryIn: wait,
+			})
 
 			select {
 			case <-time.After(wait):
@@ -103,7 +182,16 @@ func buildMultipartRequest(image []byte, params OcrParams) (*bytes.Buffer, *mult
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
-	part, err := writer.CreateFormFile("file", "image.jpg")
+	// label the upload with its real type; unknown data keeps the old JPEG label
+	format, ok := media.Sniff(image)
+	if !ok {
+		format = media.JPEG
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, format.Filename))
+	h.Set("Content-Type", format.MIME)
+	part, err := writer.CreatePart(h)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,7 +227,7 @@ func (t *TyphoonOCR) sendOcrRequest(ctx context.Context, body *bytes.Buffer, wri
 
 	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, ConnectionError{err}
 	}
 	defer resp.Body.Close()
 
@@ -149,46 +237,154 @@ func (t *TyphoonOCR) sendOcrRequest(ctx context.Context, body *bytes.Buffer, wri
 
 		switch resp.StatusCode {
 		case 429:
-			return nil, RateLimitError{string(raw)}
+			return nil, RateLimitError{Msg: string(raw), RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
 
 		case 500, 502, 503, 504:
 			return nil, ServerError{string(raw)}
 
 		default:
-			return nil, fmt.Errorf("OCR API error: %d - %s", resp.StatusCode, string(raw))
+			return nil, APIError{StatusCode: resp.StatusCode, Body: string(raw)}
 		}
 	}
 
 	return raw, nil
 }
 
-func parseOcrResponse(raw []byte) (string, error) {
+// parseOcrResponse stitches the pages together. requested is the PDF page
+// list that was sent (nil = all pages); the document's own page count comes
+// from the API's total_pages. Pages of the document that were not requested
+// are reported as failed so the caller knows the text is incomplete.
+func parseOcrResponse(raw []byte, requested []int) (string, error) {
 	var resp OcrResponse
 	if err := json.Unmarshal(raw, &resp); err != nil {
 		return "", fmt.Errorf("failed to unmarshal OCR response: %w", err)
 	}
+	var skipped []domain.PageFailure
+	if len(requested) > 0 && resp.TotalPages > 0 {
+		resp.dropPagesAfter(resp.TotalPages)
+		skipped = skippedPages(requested, resp.TotalPages)
+	}
+
+	text, failed := resp.ExtractText()
+	if len(skipped) > 0 {
+		failed = append(failed, skipped...)
+		sort.SliceStable(failed, func(i, j int) bool { return failed[i].Page < failed[j].Page })
+	}
+	if len(failed) == 0 {
+		return text, nil
+	}
 
-	return resp.ExtractText(), nil
+	// the document's page count, not how many pages came back
+	total := resp.TotalPages
+	if total == 0 {
+		total = len(resp.Results)
+	}
+	// the caller still gets the pages that worked, plus the report of the ones that didn't
+	return text, &domain.PartialOCRError{TotalPages: total, Failed: failed}
 }
 
-func (r *OcrResponse) ExtractTextWithNaturalText() string {
-	if len(r.Results) == 0 ||
-		r.Results[0].Message == nil ||
-		len(r.Results[0].Message.Choices) == 0 {
-		return ""
+// skippedPages lists the pages of a document of total pages that were not
+// in the requested page list.
+func skippedPages(requested []int, total int) []domain.PageFailure {
+	asked := make(map[int]bool, len(requested))
+	for _, p := range requested {
+		asked[p] = true
+	}
+	var skipped []domain.PageFailure
+	for p := 1; p <= total; p++ {
+		if !asked[p] {
+			skipped = append(skipped, domain.PageFailure{Page: p, Reason: "not processed: over OPENTYPHOON_PDF_MAX_PAGES"})
+		}
 	}
+	return skipped
+}
+
+// dropPagesAfter removes the results of requested pages past the end of
+// the document.
+func (r *OcrResponse) dropPagesAfter(last int) {
+	kept := r.Results[:0]
+	for i, res := range r.Results {
+		if pageNum(res, i) <= last {
+			kept = append(kept, res)
+		}
+	}
+	r.Results = kept
+}
 
-	return r.Results[0].Message.Choices[0].Message.Content
+// pages returns the results ordered by page number. Results without a page
+// number (single images) are numbered by their original position.
+func (r *OcrResponse) pages() []OcrResult {
+	pages := make([]OcrResult, len(r.Results))
+	for i, res := range r.Results {
+		if res.PageNum == nil {
+			n := i + 1
+			res.PageNum = &n
+		}
+		pages[i] = res
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		return *pages[i].PageNum < *pages[j].PageNum
+	})
+	return pages
+}
+
+func pageNum(r OcrResult, idx int) int {
+	if r.PageNum != nil {
+		return *r.PageNum
+	}
+	return idx + 1
 }
 
-func (r *OcrResponse) ExtractText() string {
-	if len(r.Results) == 0 ||
-		r.Results[0].Message == nil ||
-		len(r.Results[0].Message.Choices) == 0 {
+func (r OcrResult) content() string {
+	if r.Message == nil || len(r.Message.Choices) == 0 {
 		return ""
 	}
+	return r.Message.Choices[0].Message.Content
+}
+
+func (r OcrResult) failure(page int) (domain.PageFailure, bool) {
+	if r.Success && r.Message != nil && len(r.Message.Choices) > 0 {
+		return domain.PageFailure{}, false
+	}
+	reason := "no content returned"
+	if r.Error != nil {
+		reason = fmt.Sprint(r.Error)
+	}
+	return domain.PageFailure{Page: page, Reason: reason}, true
+}
 
-	rawContent := r.Results[0].Message.Choices[0].Message.Content
+func (r *OcrResponse) ExtractTextWithNaturalText() string {
+	var parts []string
+	for _, p := range r.pages() {
+		if c := p.content(); c != "" {
+			parts = append(parts, c)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ExtractText stitches the text of every successful page together in page
+// order and reports the pages that failed.
+func (r *OcrResponse) ExtractText() (string, []domain.PageFailure) {
+	var (
+		parts  []string
+		failed []domain.PageFailure
+	)
+
+	for i, p := range r.pages() {
+		if f, ok := p.failure(pageNum(p, i)); ok {
+			failed = append(failed, f)
+			continue
+		}
+		if text := naturalText(p.content()); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n"), failed
+}
+
+func naturalText(rawContent string) string {
 	if rawContent == "" {
 		return ""
 	}
@@ -205,3 +401,81 @@ func (r *OcrResponse) ExtractText() string {
 	// Fallback: if it's not JSON, just return the raw content
 	return rawContent
 }
+
+// parseRetryAfter understands both forms of the header: delay-seconds and HTTP-date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ===== PDF helpers =====
+
+var pdfPageRe = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// pdfPages selects the pages to OCR: the first maxPdfPages. The file is not
+// parsed for its page count (incremental updates and object streams make
+// that unreliable); pages past the end are dropped from the response using
+// the count the API reports.
+func (t *TyphoonOCR) pdfPages() []int {
+	if t.maxPdfPages <= 0 {
+		return nil
+	}
+	pages := make([]int, t.maxPdfPages)
+	for i := range pages {
+		pages[i] = i + 1
+	}
+	return pages
+}
+
+// retryPages returns the page list cut to the document's real length when
+// the API refused a list that runs past the end of the PDF, nil when there
+// is nothing shorter to ask for. The length is the total_pages the API
+// sends with the refusal, or else the page objects counted in the file.
+func retryPages(err error, pages []int, pdf []byte) []int {
+	var apiErr APIError
+	if len(pages) == 0 || !errors.As(err, &apiErr) {
+		return nil
+	}
+	if apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return nil
+	}
+	total := statedTotalPages([]byte(apiErr.Body))
+	if total <= 0 {
+		total = len(pdfPageRe.FindAll(pdf, -1))
+	}
+	if total <= 0 {
+		return nil
+	}
+	kept := make([]int, 0, len(pages))
+	for _, p := range pages {
+		if p <= total {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == 0 || len(kept) == len(pages) {
+		return nil
+	}
+	return kept
+}
+
+// statedTotalPages reads the total_pages field of an API error body, 0 if
+// the body is not JSON or does not carry it.
+func statedTotalPages(body []byte) int {
+	var stated struct {
+		TotalPages int `json:"total_pages"`
+	}
+	if err := json.Unmarshal(body, &stated); err != nil {
+		return 0
+	}
+	return stated.TotalPages
+}
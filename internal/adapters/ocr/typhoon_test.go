@@ -0,0 +1,186 @@
+package ocr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// page is one result of an OCR response; an empty text makes it a failure.
+type page struct {
+	num  int
+	text string
+}
+
+func ocrBody(total int, pages ...page) []byte {
+	resp := OcrResponse{TotalPages: total}
+	for _, p := range pages {
+		n := p.num
+		res := OcrResult{PageNum: &n, Success: p.text != ""}
+		if p.text != "" {
+			content, _ := json.Marshal(map[string]string{"natural_text": p.text})
+			res.Message = &OcrMessage{Choices: []OcrChoice{{Message: OcrChatMessage{Content: string(content)}}}}
+		} else {
+			res.Error = "timeout"
+		}
+		resp.Results = append(resp.Results, res)
+	}
+	raw, _ := json.Marshal(resp)
+	return raw
+}
+
+func pageList(n int) []int {
+	pages := make([]int, n)
+	for i := range pages {
+		pages[i] = i + 1
+	}
+	return pages
+}
+
+func TestParseOcrResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        []byte
+		requested  []int
+		want       string
+		wantFailed []int
+		wantTotal  int
+	}{
+		{
+			name: "single image without page numbers",
+			raw:  []byte(`{"results":[{"success":true,"message":{"choices":[{"message":{"content":"plain text"}}]}}]}`),
+			want: "plain text",
+		},
+		{
+			name:      "pages stitched in page order",
+			raw:       ocrBody(3, page{3, "three"}, page{1, "one"}, page{2, "two"}),
+			requested: pageList(3),
+			want:      "one\n\ntwo\n\nthree",
+		},
+		{
+			name:       "failed page reported, the rest kept",
+			raw:        ocrBody(3, page{1, "one"}, page{2, ""}, page{3, "three"}),
+			requested:  pageList(3),
+			want:       "one\n\nthree",
+			wantFailed: []int{2},
+			wantTotal:  3,
+		},
+		{
+			name:      "requested pages past the end dropped",
+			raw:       ocrBody(2, page{1, "one"}, page{2, "two"}, page{3, ""}, page{4, ""}),
+			requested: pageList(4),
+			want:      "one\n\ntwo",
+		},
+		{
+			name:       "pages past the cap reported",
+			raw:        ocrBody(5, page{1, "one"}, page{2, "two"}),
+			requested:  pageList(2),
+			want:       "one\n\ntwo",
+			wantFailed: []int{3, 4, 5},
+			wantTotal:  5,
+		},
+		{
+			name:       "failed and skipped pages in order",
+			raw:        ocrBody(4, page{1, ""}, page{2, "two"}),
+			requested:  pageList(2),
+			want:       "two",
+			wantFailed: []int{1, 3, 4},
+			wantTotal:  4,
+		},
+		{
+			name: "all pages sent, nothing skipped",
+			raw:  ocrBody(5, page{1, "one"}, page{2, "two"}),
+			want: "one\n\ntwo",
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseOcrResponse(tt.raw, tt.requested)
+		if got != tt.want {
+			t.Errorf("%s: text = %q, want %q", tt.name, got, tt.want)
+		}
+		var partial *domain.PartialOCRError
+		if !errors.As(err, &partial) {
+			if err != nil || tt.wantFailed != nil {
+				t.Errorf("%s: err = %v, want failed pages %v", tt.name, err, tt.wantFailed)
+			}
+			continue
+		}
+		if got := partial.FailedPageNumbers(); !reflect.DeepEqual(got, tt.wantFailed) {
+			t.Errorf("%s: failed pages = %v, want %v", tt.name, got, tt.wantFailed)
+		}
+		if partial.TotalPages != tt.wantTotal {
+			t.Errorf("%s: total pages = %d, want %d", tt.name, partial.TotalPages, tt.wantTotal)
+		}
+	}
+}
+
+func TestRetryPages(t *testing.T) {
+	threePages := []byte("%PDF-1.7 /Type /Pages /Type /Page /Type /Page /Type/Page")
+	tests := []struct {
+		name  string
+		err   error
+		pages []int
+		pdf   []byte
+		want  []int
+	}{
+		{"count from total_pages", APIError{422, `{"detail":"bad pages","total_pages":2}`}, pageList(10), nil, []int{1, 2}},
+		{"count from the file", APIError{400, `page 4 is out of range`}, pageList(10), threePages, []int{1, 2, 3}},
+		{"total_pages wins over the file", APIError{400, `{"total_pages":1}`}, pageList(10), threePages, []int{1}},
+		{"list already within the document", APIError{400, `{"total_pages":12}`}, pageList(10), nil, nil},
+		{"count unknown", APIError{400, `bad request`}, pageList(10), []byte("%PDF-1.7"), nil},
+		{"no page list sent", APIError{400, `{"total_pages":2}`}, nil, nil, nil},
+		{"other status", APIError{403, `{"total_pages":2}`}, pageList(10), nil, nil},
+		{"not an API error", ServerError{`{"total_pages":2}`}, pageList(10), nil, nil},
+	}
+	for _, tt := range tests {
+		if got := retryPages(tt.err, tt.pages, tt.pdf); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: retryPages = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExtractTextShrinksPageList(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages := r.FormValue("pages")
+		calls = append(calls, pages)
+		if pages != "[1,2]" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"detail":"invalid pages","total_pages":2}`)
+			return
+		}
+		w.Write(ocrBody(2, page{1, "one"}, page{2, "two"}))
+	}))
+	defer srv.Close()
+
+	ocr := &TyphoonOCR{baseURL: srv.URL, httpClient: srv.Client(), defaultOcr: OcrParams{TaskType: "default"}, maxPdfPages: 10}
+	got, err := ocr.ExtractText(context.Background(), []byte("%PDF-1.7"))
+	if err != nil || got != "one\n\ntwo" {
+		t.Fatalf("ExtractText = %q, %v; want both pages", got, err)
+	}
+	if want := []string{"[1,2,3,4,5,6,7,8,9,10]", "[1,2]"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("page lists sent = %v, want %v", calls, want)
+	}
+}
+
+func TestExtractTextInvalidInput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"detail":"unsupported file"}`)
+	}))
+	defer srv.Close()
+
+	ocr := &TyphoonOCR{baseURL: srv.URL, httpClient: srv.Client(), defaultOcr: OcrParams{TaskType: "default"}, maxPdfPages: 10}
+	_, err := ocr.ExtractText(context.Background(), []byte("%PDF-1.7"))
+	if !errors.Is(err, domain.ErrInvalidInput) || !strings.Contains(err.Error(), "unsupported file") {
+		t.Errorf("err = %v, want the API's invalid input error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Check implements ports.HealthPort: at least one Ollama host has to answer
+// /api/tags with the model we generate with pulled. Every host is probed, so
+// the check also feeds the pool's ejection state; a probe cut short by the
+// check's own deadline or by shutdown says nothing about the host and is not
+// counted against it.
+func (o *OllamaAdapter) Check(ctx context.Context, _ string) (bool, string) {
+	errs := make([]error, len(o.pool.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range o.pool.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			errs[i] = o.probe(ctx, ep)
+			switch {
+			case errs[i] == nil:
+				o.pool.recordSuccess(ep)
+			case ctx.Err() == nil:
+				o.pool.recordFailure(ep, errs[i])
+			}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	healthy := 0
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		if err == nil {
+			healthy++
+			msgs[i] = o.pool.endpoints[i].baseURL + " ok"
+		} else {
+			msgs[i] = err.Error()
+		}
+	}
+	summary := fmt.Sprintf("%d/%d hosts serving %s: %s", healthy, len(errs), o.model, strings.Join(msgs, "; "))
+	return healthy > 0, summary
+}
+
+// probe checks one host: /api/tags must answer and list the model.
+func (o *OllamaAdapter) probe(ctx context.Context, ep *endpoint) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.baseURL+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama unreachable at %s: %v", ep.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s /api/tags returned %d", ep.baseURL, resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("%s: invalid /api/tags response: %v", ep.baseURL, err)
+	}
+
+	for _, m := range tags.Models {
+		if m.Name == o.model || strings.TrimSuffix(m.Name, ":latest") == o.model {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s not found on %s", o.model, ep.baseURL)
+}
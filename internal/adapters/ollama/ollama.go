@@ -1,6 +1,7 @@
 package ollama
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -10,67 +11,276 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/llm"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/env"
 	"github.com/rs/zerolog"
 )
 
 var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
 
+const (
+	ollamaTimeout = 5 * time.Minute
+	defaultModel  = "modjot-ai-v4"
+)
+
+// errOllamaTimeout is the cause of contexts ended by ollamaTimeout rather
+// than by the caller.
+var errOllamaTimeout = errors.New("ollama generation timed out")
+
+// ConnectionError means Ollama could not be reached (or the call timed out).
+type ConnectionError struct{ Err error }
+
+func (e ConnectionError) Error() string        { return "ollama API connection error: " + e.Err.Error() }
+func (e ConnectionError) Unwrap() error        { return e.Err }
+func (e ConnectionError) Is(target error) bool { return target == domain.ErrUnavailable }
+
+// APIError is a non-200 answer from Ollama.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("ollama API error: %d - %s", e.StatusCode, e.Body)
+}
+func (e APIError) Is(target error) bool {
+	return target == domain.ErrUnavailable && e.StatusCode >= http.StatusInternalServerError
+}
+
 type OllamaAdapter struct {
-	baseURL    string
+	pool       *pool
+	model      string
 	httpClient *http.Client
+
+	// structuredOutput sends the Transaction JSON Schema as "format"; it is
+	// switched off at runtime when the server is too old to accept it.
+	structuredOutput  bool
+	schemaUnsupported atomic.Bool
+
+	// maxReprompts bounds how often invalid JSON is sent back for correction.
+	maxReprompts int
+	// fixMismatch re-prompts once when the items do not add up to the
+	// receipt total.
+	fixMismatch bool
 }
 
-func NewOllamaAdapter() *OllamaAdapter {
-	hostIp := os.Getenv("HOST_IP")
-	if hostIp == "" {
-		log.Fatal("missing HOST_IP")
+// NewOllamaAdapter configures the adapter from the environment. Ejected
+// hosts are re-probed in the background until ctx is done.
+func NewOllamaAdapter(ctx context.Context) *OllamaAdapter {
+	// OLLAMA_HOSTS lists every GPU box; HOST_IP is the single-host setup
+	hosts := env.List("OLLAMA_HOSTS")
+	if len(hosts) == 0 {
+		hostIp := os.Getenv("HOST_IP")
+		if hostIp == "" {
+			log.Fatal("missing OLLAMA_HOSTS or HOST_IP")
+		}
+		hosts = []string{hostIp}
 	}
-	baseURL := fmt.Sprintf("http://%s:11434", hostIp)
 
-	return &OllamaAdapter{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
+	o := &OllamaAdapter{
+		pool: newPool(hosts,
+			env.Int("OLLAMA_EJECT_AFTER_FAILURES", 2),
+			env.Duration("OLLAMA_EJECT_DURATION", 30*time.Second),
+			env.Duration("OLLAMA_MAX_EJECT_DURATION", 5*time.Minute),
+		),
+		model:      defaultModel,
+		httpClient: &http.Client{Transport: newTransport()},
+
+		structuredOutput: env.Bool("OLLAMA_STRUCTURED_OUTPUT", true),
+		maxReprompts:     env.Int("OLLAMA_MAX_REPROMPTS", 1),
+		fixMismatch:      env.Bool("OLLAMA_FIX_MISMATCH", false),
 	}
+	logger.Info().Stringer("hosts", o.pool).Msg("ollama hosts configured")
+
+	go o.pool.probeLoop(ctx, env.Duration("OLLAMA_PROBE_INTERVAL", 10*time.Second), o.probe)
+	return o
 }
+
 func (o *OllamaAdapter) ParseOcrResponseToJson(ctx context.Context, text string, categories []string) (*domain.Transaction, error) {
 	if text == "" {
 		return nil, errors.New("empty OCR text")
 	}
-	preOCR := PreprocessOCR(text)
-	payload := buildAIRequest(preOCR, categories)
+	preOCR := llm.PreprocessOCR(text)
+	payload := buildAIRequest(o.model, preOCR, categories)
+	if o.structuredOutput && !o.schemaUnsupported.Load() {
+		payload.Format = llm.TransactionSchema(categories)
+	}
 
 	logger.Info().Str("prompt", payload.Prompt).Msg("full prompt")
 
+	// tie Ollama timeout to incoming ctx; it has to cover reading the body
+	// too, so it cannot live inside sendRequest
+	ctx, cancel := context.WithTimeoutCause(ctx, ollamaTimeout, errOllamaTimeout)
+	defer cancel()
+
+	gen := func(ctx context.Context, prompt string) (string, error) {
+		payload.Prompt = prompt
+		return o.generate(ctx, &payload, nil)
+	}
+	return llm.ParseTransaction(ctx, gen, payload.Prompt, o.maxReprompts, o.fixMismatch)
+}
+
+// StreamTransaction implements ports.StreamingLLM. The first generation is
+// streamed and every completed item is passed to onItem while the model is
+// still writing; re-prompts, if needed, run without streaming. Cancelling
+// ctx closes the connection, which makes Ollama stop generating.
+func (o *OllamaAdapter) StreamTransaction(ctx context.Context, text string, categories []string, onItem func(domain.TransactionItem)) (*domain.Transaction, error) {
+	if text == "" {
+		return nil, errors.New("empty OCR text")
+	}
+	preOCR := llm.PreprocessOCR(text)
+	payload := buildAIRequest(o.model, preOCR, categories)
+	if o.structuredOutput && !o.schemaUnsupported.Load() {
+		payload.Format = llm.TransactionSchema(categories)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, ollamaTimeout, errOllamaTimeout)
+	defer cancel()
+
+	items := llm.NewItemStream(onItem)
+	streamed := false
+	gen := func(ctx context.Context, prompt string) (string, error) {
+		payload.Prompt = prompt
+		if streamed {
+			return o.generate(ctx, &payload, nil)
+		}
+		streamed = true
+		return o.generate(ctx, &payload, items.Write)
+	}
+	return llm.ParseTransaction(ctx, gen, payload.Prompt, o.maxReprompts, o.fixMismatch)
+}
+
+// ParseTransactions implements ports.MultiTransactionLLM with the
+// multi-transaction prompt and schema. The text is typed, not OCR output,
+// so it is not preprocessed.
+func (o *OllamaAdapter) ParseTransactions(ctx context.Context, text string, categories []string) ([]domain.Entry, error) {
+	if text == "" {
+		return nil, errors.New("empty text")
+	}
+	payload := buildAIRequest(o.model, text, categories)
+	payload.Prompt = llm.BuildEntriesPrompt(text, categories)
+	if o.structuredOutput && !o.schemaUnsupported.Load() {
+		payload.Format = llm.TransactionsSchema(categories)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, ollamaTimeout, errOllamaTimeout)
+	defer cancel()
+
+	gen := func(ctx context.Context, prompt string) (string, error) {
+		payload.Prompt = prompt
+		return o.generate(ctx, &payload, nil)
+	}
+	return llm.ParseTransactions(ctx, gen, payload.Prompt, o.maxReprompts)
+}
+
+// CacheKey implements ports.CacheKeyer.
+func (o *OllamaAdapter) CacheKey(text string, categories []string) string {
+	format := "json"
+	if o.structuredOutput {
+		format = "schema"
+	}
+	return llm.CacheKey(o.model, llm.Settings(format, o.maxReprompts, o.fixMismatch), text, categories)
+}
+
+// generate runs one generation and returns the model's raw response text.
+// With onChunk set the response is streamed and each piece is passed to it
+// as it arrives. A server that rejects the JSON schema format is retried
+// once with plain "json" and remembered.
+func (o *OllamaAdapter) generate(ctx context.Context, payload *AIRequest, onChunk func(string)) (string, error) {
 	// pass ctx down so gRPC cancel/timeout propagates
-	raw, err := o.sendRequest(ctx, payload)
+	out, err := o.route(ctx, *payload, onChunk)
+	if isSchemaFormatRejected(err) && payload.Format != "json" {
+		logger.Warn().Err(err).Msg("ollama does not accept a JSON schema format, falling back to \"json\"")
+		o.schemaUnsupported.Store(true)
+		payload.Format = "json"
+		out, err = o.route(ctx, *payload, onChunk)
+	}
+	return out, err
+}
+
+// route sends a generation to the least busy host and fails over to the
+// next one on connection errors, timeouts and 5xx while ctx allows it. A
+// stream that already delivered chunks is not restarted elsewhere.
+func (o *OllamaAdapter) route(ctx context.Context, payload AIRequest, onChunk func(string)) (string, error) {
+	payload.Stream = onChunk != nil
+	delivered := false
+	emit := func(chunk string) {
+		delivered = true
+		onChunk(chunk)
+	}
+
+	tried := map[*endpoint]bool{}
+	var lastErr error
+	for ep := o.pool.pick(tried); ep != nil; ep = o.pool.pick(tried) {
+		tried[ep] = true
+
+		done := ep.begin()
+		start := time.Now()
+		out, err := o.generateOn(ctx, ep.baseURL, payload, emit)
+		failed := isHostFailure(ctx, err)
+		done(failed)
+
+		logger.Info().
+			Str("host", ep.baseURL).
+			Dur("latency", time.Since(start)).
+			Bool("failed", failed).
+			Msg("ollama request routed")
+
+		if !failed {
+			if ctx.Err() == nil {
+				o.pool.recordSuccess(ep)
+			}
+			return out, err
+		}
+
+		o.pool.recordFailure(ep, err)
+		lastErr = err
+		if ctx.Err() != nil || delivered {
+			break
+		}
+		logger.Warn().Err(err).Str("host", ep.baseURL).Msg("failing over to next ollama host")
+	}
+	return "", lastErr
+}
+
+// isHostFailure tells whether err says something about the host's health.
+// Bad requests do not, and neither does a call the caller cancelled or gave
+// too little time; only ollamaTimeout running out counts against the host.
+func isHostFailure(ctx context.Context, err error) bool {
+	if !errors.Is(err, domain.ErrUnavailable) {
+		return false
+	}
+	return ctx.Err() == nil || context.Cause(ctx) == errOllamaTimeout
+}
+
+func (o *OllamaAdapter) generateOn(ctx context.Context, baseURL string, payload AIRequest, onChunk func(string)) (string, error) {
+	raw, err := o.sendRequest(ctx, baseURL, payload)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	defer raw.Body.Close()
 
+	if payload.Stream {
+		return parseStreamOllamaResponse(raw, onChunk)
+	}
 	return parseNonStreamOllamaResponse(raw)
 }
 
-func (o *OllamaAdapter) sendRequest(ctx context.Context, payload AIRequest) (*http.Response, error) {
+func (o *OllamaAdapter) sendRequest(ctx context.Context, baseURL string, payload AIRequest) (*http.Response, error) {
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		logger.Error().Err(err).Msg("Error marshalling JSON")
 		return nil, fmt.Errorf("internal error")
 	}
 
-	url := fmt.Sprintf("%s%s", o.baseURL, "/api/generate")
-	ollamaTimeout := 5 * time.Minute
+	url := fmt.Sprintf("%s%s", baseURL, "/api/generate")
 
-	// tie Ollama timeout to incoming ctx
-	ollamaCtx, cancel := context.WithTimeout(ctx, ollamaTimeout)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ollamaCtx, http.MethodPost, url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -78,98 +288,112 @@ func (o *OllamaAdapter) sendRequest(ctx context.Context, payload AIRequest) (*ht
 
 	raw, err := o.httpClient.Do(req)
 	if err != nil {
-		logger.Error().Err(err).Msg("Error connecting to Ollama API")
-		return nil, fmt.Errorf("ollama API connection error")
+		logger.Error().Err(err).Str("host", baseURL).Msg("Error connecting to Ollama API")
+		return nil, ConnectionError{err}
 	}
 
 	if raw.StatusCode != http.StatusOK {
 		defer raw.Body.Close()
 		body, _ := io.ReadAll(raw.Body)
-		return nil, fmt.Errorf("ollama API error: %d - %s", raw.StatusCode, string(body))
+		return nil, APIError{StatusCode: raw.StatusCode, Body: string(body)}
 	}
 
 	return raw, nil
 }
 
-func parseNonStreamOllamaResponse(resp *http.Response) (*domain.Transaction, error) {
+// isSchemaFormatRejected recognises the 400 that Ollama versions before 0.5
+// return when "format" is an object instead of a string.
+func isSchemaFormatRejected(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) &&
+		apiErr.StatusCode == http.StatusBadRequest &&
+		strings.Contains(apiErr.Body, "format")
+}
+
+func parseNonStreamOllamaResponse(resp *http.Response) (string, error) {
 	// 1) decode Ollama's response object
 	var ollamaResp struct {
-		Model    string `json:"model"`
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Model      string `json:"model"`
+		Response   string `json:"response"`
+		Done       bool   `json:"done"`
+		DoneReason string `json:"done_reason"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
 		logger.Error().Err(err).Msg("failed to decode ollama response json")
-		return nil, err
+		return "", ConnectionError{err}
 	}
 
 	// 2) log raw text from Ollama (this is what you wanted)
 	logger.Info().
 		Str("model", ollamaResp.Model).
+		Str("done_reason", ollamaResp.DoneReason).
 		Str("full_response", ollamaResp.Response).
 		Msg("ollama full response")
 
 	if ollamaResp.Response == "" {
-		return nil, errors.New("ollama returned empty response")
-	}
-
-	// 3) parse the JSON string from `response` into your domain.Transaction
-	var finalJSON domain.Transaction
-	if err := json.Unmarshal([]byte(ollamaResp.Response), &finalJSON); err != nil {
-		logger.Error().
-			Err(err).
-			Str("raw_text", ollamaResp.Response).
-			Msg("failed to unmarshal transaction JSON from ollama")
-		return nil, err
+		return "", llm.InvalidOutputError{Err: errors.New("ollama returned empty response")}
 	}
+	return ollamaResp.Response, nil
+}
 
-	for i := range finalJSON.Items {
-		if finalJSON.Items[i].Category == "" {
-			finalJSON.Items[i].Category = "อื่นๆ"
+// parseStreamOllamaResponse reads the NDJSON chunks of a streamed
+// generation, passing each piece of text to onChunk, and returns the whole
+// response.
+func parseStreamOllamaResponse(resp *http.Response, onChunk func(string)) (string, error) {
+	var (
+		full  strings.Builder
+		chunk struct {
+			Model      string `json:"model"`
+			Response   string `json:"response"`
+			Done       bool   `json:"done"`
+			DoneReason string `json:"done_reason"`
+			Error      string `json:"error"`
+		}
+	)
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		chunk.Response = ""
+		if err := json.Unmarshal(sc.Bytes(), &chunk); err != nil {
+			logger.Error().Err(err).Msg("failed to decode ollama stream chunk")
+			return "", ConnectionError{err}
 		}
+		if chunk.Error != "" {
+			// errors after the 200 header arrive in the stream
+			return "", APIError{StatusCode: http.StatusInternalServerError, Body: chunk.Error}
+		}
+		if chunk.Response != "" {
+			full.WriteString(chunk.Response)
+			onChunk(chunk.Response)
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", ConnectionError{err}
+	}
+	if !chunk.Done {
+		return "", ConnectionError{io.ErrUnexpectedEOF}
 	}
 
-	return &finalJSON, nil
-}
+	logger.Info().
+		Str("model", chunk.Model).
+		Str("done_reason", chunk.DoneReason).
+		Str("full_response", full.String()).
+		Msg("ollama full response (streamed)")
 
-func buildAIRequest(ocrText string, categories []string) AIRequest {
-	prompt := fmt.Sprintf(
-		`Return only minified JSON in one line. No comments. No markdown.
-
-CRITICAL RULES:
-- Categories MUST be exactly one of: %v. Never invent new categories.
-- category is REQUIRED for every item.
-- NEVER omit category.
-- If unsure, use the closest match from the categories list.
-- Every item MUST contain all three fields: title, price, category.
-- Only real purchased products may appear in items[].
-- NEVER include store name, branch, receipt header, tax id, POS id, totals, VAT, CASH, Change, discount lines, or thank-you text.
-- Any token where numbers touch letters (example: "470X", "3S") is a PRODUCT CODE, NOT a price.
-- A price MUST be a standalone decimal number at the END of a product line.
-- Lines containing quantity/unit patterns such as "@", "PCS", "หน่วย" are NOT products.
-- Any line starting with a number followed by "@" is NEVER a product.
-- Quantity/unit lines belong to the previous product and must be merged into that product.
-- Prefer including uncertain items rather than dropping them unless clearly a header/total.
-- Remove prefixes like "1P", "2P", "A#", "P#", "A ", "P ".
-- Titles must be short product names only.
-- date MUST be ISO-8601. Include time if present: YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS(+TZ)
-- If a line appears to be a product but is messy OCR, still include it.
-- Only drop lines that are clearly totals, VAT, CASH, Change, receipt numbers, or discounts.
-- If price is unclear, infer from nearest decimal number.
-
-OUTPUT JSON SCHEMA:
-{"title":string,"date":string,"items":[{"title":string,"price":number,"category":string}]}
-
-OCR TEXT:
-%s`,
-		categories,
-		ocrText,
-	)
+	if full.Len() == 0 {
+		return "", llm.InvalidOutputError{Err: errors.New("ollama returned empty response")}
+	}
+	return full.String(), nil
+}
 
+func buildAIRequest(model, ocrText string, categories []string) AIRequest {
 	return AIRequest{
-		Model:  "modjot-ai-v4",
-		Prompt: prompt,
+		Model:  model,
+		Prompt: llm.BuildPrompt(ocrText, categories),
 		Stream: false,
 		Format: "json",
 		Options: &AIOptions{
@@ -178,81 +402,3 @@ OCR TEXT:
 		},
 	}
 }
-
-func CleanOCR(raw string) string {
-	s := raw
-
-	// remove ASCII table chars
-	s = regexp.MustCompile(`[|]+`).ReplaceAllString(s, " ")
-	s = regexp.MustCompile(`[-_=]{2,}`).ReplaceAllString(s, " ")
-
-	// remove unicode box drawing
-	s = regexp.MustCompile(`[│─┼┌┐└┘╔╗╚╝═]+`).ReplaceAllString(s, " ")
-
-	// collapse multiple newlines
-	s = regexp.MustCompile(`\n{2,}`).ReplaceAllString(s, "\n")
-
-	s = strings.TrimSpace(s)
-
-	// normalize spaces
-	s = regexp.MustCompile(`\s{2,}`).ReplaceAllString(s, " ")
-
-	return s
-}
-
-func MergeQtyLines(s string) string {
-	lines := strings.Split(s, "\n")
-
-	qtyRe := regexp.MustCompile(`^\s*\d+(\.\d+)?@\s*\d+(\.\d+)?`)
-
-	var out []string
-
-	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-
-		if qtyRe.MatchString(line) && len(out) > 0 {
-			out[len(out)-1] = out[len(out)-1] + " " + line
-			continue
-		}
-
-		out = append(out, line)
-	}
-
-	return strings.Join(out, "\n")
-}
-
-func FixThaiOCR(s string) string {
-	for bad, good := range thaiFix {
-		s = strings.ReplaceAll(s, bad, good)
-	}
-	return s
-}
-
-func NormalizeNumbers(s string) string {
-	// remove spaces inside numbers: 1,     000 -> 1,000
-	re := regexp.MustCompile(`(\d)[,\s]+(\d{3})`)
-	return re.ReplaceAllString(s, `$1$2`)
-}
-
-func PreprocessOCR(raw string) string {
-	logger.Info().Str("raw_ocr", raw).Msg("raw OCR text")
-
-	s := CleanOCR(raw)
-
-	// 1. แก้เลขก่อน เพราะมีผลต่อ parsing ทั้งหมด
-	s = NormalizeNumbers(s)
-	s = SplitMergedPrices(s)
-
-	// 2. จัดโครงสร้างบรรทัด
-	s = MergeQtyLines(s)
-
-	// 3. แก้คำ OCR ไทย
-	s = FixThaiOCR(s)
-
-	return s
-}
-
-func SplitMergedPrices(s string) string {
-	re := regexp.MustCompile(`(\d+\.\d{2})(\d+\.\d{2})`)
-	return re.ReplaceAllString(s, `$1 $2`)
-}
@@ -0,0 +1,214 @@
+package ollama
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// hostStats exports per-host counters: ollama_hosts.<url>.{requests,failures,
+// ejections,in_flight,latency_ms_total}.
+var hostStats = expvar.NewMap("ollama_hosts")
+
+// endpoint is one Ollama host of the pool.
+type endpoint struct {
+	baseURL  string
+	inFlight atomic.Int64
+	stats    *expvar.Map
+
+	mu           sync.Mutex
+	failures     int // consecutive failures
+	ejections    int // consecutive ejections, drives the backoff
+	ejectedUntil time.Time
+}
+
+func (e *endpoint) ejected(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return now.Before(e.ejectedUntil)
+}
+
+// pool balances requests over Ollama hosts by least outstanding requests.
+// Hosts that fail (connection errors, timeouts, 5xx) maxFailures times in a
+// row are ejected for an exponentially growing period; ejected hosts are
+// re-probed in the background and return as soon as a probe succeeds.
+type pool struct {
+	endpoints []*endpoint
+	next      atomic.Uint64 // rotates the scan start so ties spread evenly
+
+	maxFailures  int
+	baseEjection time.Duration
+	maxEjection  time.Duration
+}
+
+func newPool(hosts []string, maxFailures int, baseEjection, maxEjection time.Duration) *pool {
+	p := &pool{
+		maxFailures:  maxFailures,
+		baseEjection: baseEjection,
+		maxEjection:  maxEjection,
+	}
+	for _, h := range hosts {
+		ep := &endpoint{baseURL: normalizeHost(h), stats: new(expvar.Map).Init()}
+		hostStats.Set(ep.baseURL, ep.stats)
+		p.endpoints = append(p.endpoints, ep)
+	}
+	return p
+}
+
+// normalizeHost accepts "10.0.0.5", "10.0.0.5:11434" or a full URL.
+func normalizeHost(h string) string {
+	h = strings.TrimSuffix(strings.TrimSpace(h), "/")
+	if !strings.Contains(h, "://") {
+		h = "http://" + h
+	}
+	if u, err := url.Parse(h); err == nil && u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "11434")
+		h = u.String()
+	}
+	return h
+}
+
+// pick returns the available host with the fewest requests in flight,
+// skipping hosts already tried for this request. When every untried host is
+// ejected it returns the one whose ejection ends first rather than failing
+// outright. It returns nil once every host has been tried.
+func (p *pool) pick(tried map[*endpoint]bool) *endpoint {
+	now := time.Now()
+	n := len(p.endpoints)
+	start := int(p.next.Add(1) % uint64(n))
+
+	var best, fallback *endpoint
+	var fallbackUntil time.Time
+	for i := 0; i < n; i++ {
+		ep := p.endpoints[(start+i)%n]
+		if tried[ep] {
+			continue
+		}
+		if ep.ejected(now) {
+			ep.mu.Lock()
+			until := ep.ejectedUntil
+			ep.mu.Unlock()
+			if fallback == nil || until.Before(fallbackUntil) {
+				fallback, fallbackUntil = ep, until
+			}
+			continue
+		}
+		if best == nil || ep.inFlight.Load() < best.inFlight.Load() {
+			best = ep
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return fallback
+}
+
+// begin marks a request as routed to e; the returned func records its outcome.
+func (e *endpoint) begin() func(failed bool) {
+	start := time.Now()
+	e.inFlight.Add(1)
+	e.stats.Add("in_flight", 1)
+	e.stats.Add("requests", 1)
+	return func(failed bool) {
+		e.inFlight.Add(-1)
+		e.stats.Add("in_flight", -1)
+		e.stats.Add("latency_ms_total", time.Since(start).Milliseconds())
+		if failed {
+			e.stats.Add("failures", 1)
+		}
+	}
+}
+
+func (p *pool) recordSuccess(e *endpoint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = 0
+	e.ejections = 0
+	e.ejectedUntil = time.Time{}
+}
+
+func (p *pool) recordFailure(e *endpoint, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures++
+	if e.failures < p.maxFailures || time.Now().Before(e.ejectedUntil) {
+		return
+	}
+
+	d := p.ejectionFor(e.ejections)
+	e.ejections++
+	e.ejectedUntil = time.Now().Add(d)
+	e.stats.Add("ejections", 1)
+	logger.Warn().Err(err).Str("host", e.baseURL).Dur("for", d).Msg("ejecting ollama host")
+}
+
+// ejectionFor is how long a host is ejected after n earlier consecutive
+// ejections: baseEjection doubled n times, capped at maxEjection. It doubles
+// step by step so a long run of ejections cannot overflow a shift.
+func (p *pool) ejectionFor(n int) time.Duration {
+	d := p.baseEjection
+	for i := 0; i < n && d < p.maxEjection; i++ {
+		d *= 2
+	}
+	if d > p.maxEjection || d <= 0 {
+		d = p.maxEjection
+	}
+	return d
+}
+
+// probeLoop re-probes ejected hosts every interval until ctx is done.
+func (p *pool) probeLoop(ctx context.Context, interval time.Duration, probe func(context.Context, *endpoint) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		for _, ep := range p.endpoints {
+			if !ep.ejected(now) {
+				continue
+			}
+			pctx, cancel := context.WithTimeout(ctx, interval)
+			err := probe(pctx, ep)
+			cancel()
+			if err == nil {
+				logger.Info().Str("host", ep.baseURL).Msg("ollama host passed re-probe, back in rotation")
+				p.recordSuccess(ep)
+			}
+		}
+	}
+}
+
+// newTransport is tuned for a handful of hosts serving long generations:
+// dead hosts fail fast on dial, and idle connections are kept per host.
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   false,
+	}
+}
+
+func (p *pool) String() string {
+	hosts := make([]string, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		hosts[i] = ep.baseURL
+	}
+	return fmt.Sprintf("[%s]", strings.Join(hosts, ", "))
+}
@@ -0,0 +1,133 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testPool(hosts ...string) *pool {
+	return newPool(hosts, 2, time.Second, 30*time.Second)
+}
+
+func TestPickLeastInFlight(t *testing.T) {
+	p := testPool("a", "b", "c")
+	a, b, c := p.endpoints[0], p.endpoints[1], p.endpoints[2]
+	a.inFlight.Store(3)
+	b.inFlight.Store(1)
+	c.inFlight.Store(2)
+
+	for i := 0; i < 3; i++ { // whatever host the scan starts at
+		if got := p.pick(nil); got != b {
+			t.Errorf("pick = %s, want the least busy host b", got.baseURL)
+		}
+	}
+	if got := p.pick(map[*endpoint]bool{b: true}); got != c {
+		t.Errorf("pick without b = %s, want c", got.baseURL)
+	}
+	if got := p.pick(map[*endpoint]bool{a: true, b: true, c: true}); got != nil {
+		t.Errorf("pick with every host tried = %s, want nil", got.baseURL)
+	}
+}
+
+func TestPickSkipsEjected(t *testing.T) {
+	p := testPool("a", "b", "c")
+	a, b, c := p.endpoints[0], p.endpoints[1], p.endpoints[2]
+	now := time.Now()
+	a.ejectedUntil = now.Add(time.Minute)
+	b.ejectedUntil = now.Add(time.Second)
+	a.inFlight.Store(0)
+	c.inFlight.Store(5)
+
+	if got := p.pick(nil); got != c {
+		t.Errorf("pick = %s, want c, the only host in rotation", got.baseURL)
+	}
+	// every untried host ejected: the one back soonest
+	if got := p.pick(map[*endpoint]bool{c: true}); got != b {
+		t.Errorf("pick with only ejected hosts = %s, want b", got.baseURL)
+	}
+}
+
+func TestEjection(t *testing.T) {
+	p := testPool("a")
+	ep := p.endpoints[0]
+	failure := errors.New("connection refused")
+
+	p.recordFailure(ep, failure)
+	if ep.ejected(time.Now()) {
+		t.Fatal("ejected after one failure, want maxFailures (2) in a row")
+	}
+	p.recordFailure(ep, failure)
+	if !ep.ejected(time.Now()) {
+		t.Fatal("not ejected after two failures in a row")
+	}
+	if until := time.Until(ep.ejectedUntil); until <= 0 || until > time.Second {
+		t.Errorf("first ejection lasts %v, want baseEjection (1s)", until)
+	}
+
+	// failing again while ejected does not extend it
+	until := ep.ejectedUntil
+	p.recordFailure(ep, failure)
+	if !ep.ejectedUntil.Equal(until) || ep.ejections != 1 {
+		t.Errorf("failure while ejected changed the ejection (ejections = %d)", ep.ejections)
+	}
+
+	p.recordSuccess(ep)
+	if ep.ejected(time.Now()) || ep.failures != 0 || ep.ejections != 0 {
+		t.Error("success did not put the host back in rotation with a clean record")
+	}
+}
+
+func TestEjectionBackoff(t *testing.T) {
+	p := testPool("a")
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{4, 16 * time.Second},
+		{5, 30 * time.Second},
+		{6, 30 * time.Second},
+		{64, 30 * time.Second},
+		{1 << 30, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.ejectionFor(tt.n); got != tt.want {
+			t.Errorf("ejectionFor(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCountsOnlyHostFailures(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	o := &OllamaAdapter{pool: testPool(broken.URL, slow.URL), model: defaultModel, httpClient: &http.Client{}}
+	brokenEp, slowEp := o.pool.endpoints[0], o.pool.endpoints[1]
+
+	// the broken host fails on its own; the slow one only runs out of the
+	// check's time
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		if healthy, msg := o.Check(ctx, ""); healthy {
+			t.Errorf("Check = healthy (%s), want unhealthy", msg)
+		}
+		cancel()
+	}
+	if !brokenEp.ejected(time.Now()) {
+		t.Error("host answering 500 was not ejected")
+	}
+	if slowEp.ejected(time.Now()) || slowEp.failures != 0 {
+		t.Errorf("host cut off by the check deadline has %d failures, want none", slowEp.failures)
+	}
+}
@@ -4,11 +4,11 @@ type AIRequest struct {
 	Model   string     `json:"model"`
 	Prompt  string     `json:"prompt"`
 	Stream  bool       `json:"stream"`
-	Format  string     `json:"format"`
+	Format  any        `json:"format"` // "json" or a JSON Schema object (Ollama >= 0.5)
 	Options *AIOptions `json:"options,omitempty"`
 }
 
 type AIOptions struct {
-	NumPredict int `json:"num_predict,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"`
 	Temperature float64 `json:"temperature,omitempty"`
 }
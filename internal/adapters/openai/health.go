@@ -0,0 +1,48 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Check implements ports.HealthPort: the server has to answer /models and,
+// when it lists models, serve the configured one.
+func (c *ChatAdapter) Check(ctx context.Context, _ string) (bool, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/models", nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("chat API unreachable at %s: %v", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Sprintf("chat API /models returned %d", resp.StatusCode)
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return false, "invalid /models response: " + err.Error()
+	}
+	if len(models.Data) == 0 {
+		return true, "chat API reachable"
+	}
+	for _, m := range models.Data {
+		if m.ID == c.model {
+			return true, "model " + c.model + " available"
+		}
+	}
+	return false, fmt.Sprintf("model %s not served by %s", c.model, c.baseURL)
+}
@@ -0,0 +1,209 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/llm"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/env"
+	"github.com/rs/zerolog"
+)
+
+var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+
+const chatTimeout = 5 * time.Minute
+
+// ConnectionError means the server could not be reached (or the call timed out).
+type ConnectionError struct{ Err error }
+
+func (e ConnectionError) Error() string        { return "chat API connection error: " + e.Err.Error() }
+func (e ConnectionError) Unwrap() error        { return e.Err }
+func (e ConnectionError) Is(target error) bool { return target == domain.ErrUnavailable }
+
+// APIError is a non-200 answer from the chat completions endpoint.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("chat API error: %d - %s", e.StatusCode, e.Body)
+}
+func (e APIError) Is(target error) bool {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == domain.ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return target == domain.ErrUnavailable
+	}
+	return false
+}
+
+// ChatAdapter implements ports.OllamaPort over the OpenAI compatible
+// /v1/chat/completions protocol spoken by vLLM and llama.cpp server.
+type ChatAdapter struct {
+	baseURL    string // including the /v1 prefix
+	apiKey     string
+	model      string
+	httpClient *http.Client
+
+	// responseFormat is "json_schema" (Transaction schema, strict) or
+	// "json_object"; json_schema falls back to json_object at runtime when
+	// the server rejects it.
+	responseFormat    string
+	schemaUnsupported atomic.Bool
+
+	// maxReprompts bounds how often invalid JSON is sent back for correction.
+	maxReprompts int
+	// fixMismatch re-prompts once when the items do not add up to the
+	// receipt total.
+	fixMismatch bool
+}
+
+func NewChatAdapter() *ChatAdapter {
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	if baseURL == "" {
+		log.Fatal("missing OPENAI_BASE_URL")
+	}
+
+	return &ChatAdapter{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     os.Getenv("OPENAI_API_KEY"),
+		model:      env.String("OPENAI_MODEL", "modjot-ai-v4"),
+		httpClient: &http.Client{},
+
+		responseFormat: env.String("OPENAI_RESPONSE_FORMAT", "json_schema"),
+		maxReprompts:   env.Int("OPENAI_MAX_REPROMPTS", 1),
+		fixMismatch:    env.Bool("OPENAI_FIX_MISMATCH", false),
+	}
+}
+
+func (c *ChatAdapter) ParseOcrResponseToJson(ctx context.Context, text string, categories []string) (*domain.Transaction, error) {
+	if text == "" {
+		return nil, errors.New("empty OCR text")
+	}
+	preOCR := llm.PreprocessOCR(text)
+	payload := c.buildChatRequest(llm.BuildPrompt(preOCR, categories), categories)
+
+	logger.Info().Str("prompt", payload.Messages[0].Content).Msg("full prompt")
+
+	ctx, cancel := context.WithTimeout(ctx, chatTimeout)
+	defer cancel()
+
+	gen := func(ctx context.Context, prompt string) (string, error) {
+		payload.Messages[0].Content = prompt
+		return c.generate(ctx, &payload)
+	}
+	return llm.ParseTransaction(ctx, gen, payload.Messages[0].Content, c.maxReprompts, c.fixMismatch)
+}
+
+func (c *ChatAdapter) buildChatRequest(prompt string, categories []string) ChatRequest {
+	req := ChatRequest{
+		Model:       c.model,
+		Messages:    []ChatMessage{{Role: "user", Content: prompt}},
+		Temperature: 0,
+		MaxTokens:   4096,
+		Stream:      false,
+	}
+
+	switch {
+	case c.responseFormat == "json_schema" && !c.schemaUnsupported.Load():
+		req.ResponseFormat = &ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &JSONSchema{
+				Name:   "transaction",
+				Schema: llm.TransactionSchema(categories),
+				Strict: true,
+			},
+		}
+	case c.responseFormat != "none":
+		req.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+	return req
+}
+
+// CacheKey implements ports.CacheKeyer.
+func (c *ChatAdapter) CacheKey(text string, categories []string) string {
+	return llm.CacheKey(c.model, llm.Settings(c.responseFormat, c.maxReprompts, c.fixMismatch), text, categories)
+}
+
+// generate runs one chat completion and returns the assistant's content. A
+// server that rejects json_schema is retried once with json_object and
+// remembered.
+func (c *ChatAdapter) generate(ctx context.Context, payload *ChatRequest) (string, error) {
+	content, err := c.sendRequest(ctx, *payload)
+	if isSchemaFormatRejected(err) && payload.ResponseFormat != nil && payload.ResponseFormat.Type == "json_schema" {
+		logger.Warn().Err(err).Msg("server does not accept json_schema response_format, falling back to json_object")
+		c.schemaUnsupported.Store(true)
+		payload.ResponseFormat = &ResponseFormat{Type: "json_object"}
+		content, err = c.sendRequest(ctx, *payload)
+	}
+	return content, err
+}
+
+func (c *ChatAdapter) sendRequest(ctx context.Context, payload ChatRequest) (string, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error marshalling JSON")
+		return "", fmt.Errorf("internal error")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error connecting to chat API")
+		return "", ConnectionError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var chatResp ChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		logger.Error().Err(err).Msg("failed to decode chat completion json")
+		return "", ConnectionError{err}
+	}
+	if len(chatResp.Choices) == 0 || chatResp.Choices[0].Message.Content == "" {
+		return "", llm.InvalidOutputError{Err: errors.New("chat API returned empty response")}
+	}
+
+	choice := chatResp.Choices[0]
+	logger.Info().
+		Str("model", chatResp.Model).
+		Str("finish_reason", choice.FinishReason).
+		Str("full_response", choice.Message.Content).
+		Msg("chat completion full response")
+
+	return choice.Message.Content, nil
+}
+
+// isSchemaFormatRejected recognises servers that do not support the
+// json_schema response format.
+func isSchemaFormatRejected(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) &&
+		apiErr.StatusCode == http.StatusBadRequest &&
+		strings.Contains(apiErr.Body, "response_format")
+}
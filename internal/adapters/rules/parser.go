@@ -0,0 +1,244 @@
+// Package rules is a deterministic, LLM-free receipt parser. It is much less
+// capable than the model and is meant as a fallback when the LLM backend is
+// down or returns unusable output.
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/adapters/llm"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/thaidate"
+)
+
+// amountPattern is a decimal amount in either convention: "1,234.50",
+// "1.234,50", "35.00", "35,00".
+const amountPattern = `-?\d{1,3}(?:[.,]\d{3})+[.,]\d{2}|-?\d+[.,]\d{2}`
+
+var (
+	// a product line ends in a decimal price, optionally followed by a tax
+	// flag or currency sign: "COKE 2@ 35.00 70.00 V"
+	priceLineRe = regexp.MustCompile(`^(.*?)\s*(` + amountPattern + `)\s*[A-Za-z฿]?$`)
+	// totals, taxes, payments and change are not items
+	skipRe   = regexp.MustCompile(`(?i)(\b(sub\s*total|total|vat|tax|cash|change|discount|service|rounding|credit|card|visa|master|promptpay|qr|balance|amount|net|paid)\b|รวม|ยอด|ภาษี|เงินสด|ทอน|ส่วนลด|ปัดเศษ|ชำระ|รับเงิน|บริการ)`)
+	letterRe = regexp.MustCompile(`[\p{L}]`)
+
+	// receipt-level fields, looked for on the lines skipped above
+	amountRe    = regexp.MustCompile(amountPattern)
+	percentRe   = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	taxIDRe     = regexp.MustCompile(`\b\d(?:[\s-]?\d){12}\b`)
+	receiptNoRe = regexp.MustCompile(`(?i)(?:receipt|invoice|bill|slip|inv|rcpt|เลขที่)\s*(?:no\.?|#|number)?\s*[:#]?\s*([A-Za-z0-9][A-Za-z0-9/-]{2,})`)
+	subtotalRe  = regexp.MustCompile(`(?i)\bsub\s*total\b|รวมเงิน|รวมค่าสินค้า`)
+	discountRe  = regexp.MustCompile(`(?i)\bdiscount\b|ส่วนลด`)
+	serviceRe   = regexp.MustCompile(`(?i)\bservice\b|ค่าบริการ`)
+	vatRe       = regexp.MustCompile(`(?i)\bvat\b|ภาษีมูลค่าเพิ่ม`)
+	// amounts named after VAT that are not the VAT: the base it is charged
+	// on ("Total before VAT", "Price excl. VAT", "Vatable") and the total
+	// that includes it ("Total incl. VAT", "รวมภาษีแล้ว")
+	vatExclRe   = regexp.MustCompile(`(?i)\b(before|excl\.?|excluding|exclusive\s+of|w/o|without)\s*(vat|tax)\b|\bvatable\b|ก่อน\s*(vat|ภาษี)|ไม่รวม\s*(vat|ภาษี)|ที่ต้องเสียภาษี`)
+	vatInclRe   = regexp.MustCompile(`(?i)\b(incl\.?|including|inclusive\s+of|inc\.?)\s*(vat|tax)\b|รวม\s*(vat|ภาษี)\S*\s*แล้ว`)
+	totalRe     = regexp.MustCompile(`(?i)\b(grand\s*total|net\s*total|total)\b|ยอดสุทธิ|รวมทั้งสิ้น|ยอดรวม`)
+	taxIDLineRe = regexp.MustCompile(`(?i)\btax\s*id\b|เลขประจำตัวผู้เสียภาษี`)
+	paymentRes  = []struct {
+		re     *regexp.Regexp
+		method string
+	}{
+		{regexp.MustCompile(`(?i)\b(promptpay|qr)\b|พร้อมเพย์`), domain.PaymentQR},
+		{regexp.MustCompile(`(?i)\b(truemoney|wallet|line\s*pay|shopee\s*pay)\b`), domain.PaymentEWallet},
+		{regexp.MustCompile(`(?i)\b(credit|card|visa|master(card)?)\b|บัตรเครดิต`), domain.PaymentCard},
+		{regexp.MustCompile(`(?i)\bcash\b|เงินสด`), domain.PaymentCash},
+	}
+)
+
+// ErrNoItems means no product line was recognised.
+var ErrNoItems = fmt.Errorf("%w: rule-based parser found no items", domain.ErrInvalidOutput)
+
+type Parser struct {
+	loc *time.Location // of dates printed without an offset
+}
+
+func NewParser(loc *time.Location) *Parser { return &Parser{loc: loc} }
+
+// ParseOcrResponseToJson implements ports.OllamaPort without a model. The
+// result is marked with domain.SourceRules.
+func (p *Parser) ParseOcrResponseToJson(ctx context.Context, text string, categories []string) (*domain.Transaction, error) {
+	if text == "" {
+		return nil, errors.New("empty OCR text")
+	}
+	lines := strings.Split(llm.PreprocessOCR(text), "\n")
+
+	now := time.Now().In(p.loc)
+	tr := &domain.Transaction{Source: domain.SourceRules, Items: []domain.TransactionItem{}}
+	if d, ok := thaidate.Find(text, now); ok {
+		tr.Date = d.String()
+	}
+	category := fallbackCategory(categories)
+	var pending string // a name line whose price is on the next line
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if readReceiptField(tr, line) {
+			pending = ""
+			continue
+		}
+		if skipRe.MatchString(line) {
+			pending = ""
+			continue
+		}
+
+		// "COKE 2@35.00 70.00": the quantity part first, then the price
+		rest, q, hasQty := llm.SplitQuantity(line)
+		m := priceLineRe.FindStringSubmatch(rest)
+		if m == nil && !hasQty {
+			if _, isDate := thaidate.Find(line, now); letterRe.MatchString(line) && !isDate {
+				if tr.Title == "" {
+					tr.Title = line
+				} else {
+					pending = line
+				}
+			}
+			continue
+		}
+
+		var price domain.Amount // left 0 for "2@35.00" alone, llm.FinishItems multiplies
+		if m != nil {
+			var err error
+			price, err = domain.ParseAmount(m[2])
+			if err != nil || price.Sign() <= 0 {
+				continue // discounts and voids
+			}
+			rest = m[1]
+		}
+		title := strings.TrimSpace(rest)
+		if !letterRe.MatchString(title) {
+			title = pending
+		}
+		pending = ""
+		if title == "" {
+			continue
+		}
+		item := domain.TransactionItem{Title: title, Price: price, Category: category}
+		if hasQty {
+			item.Quantity, item.UnitPrice, item.Unit = q.Qty, q.UnitPrice, q.Unit
+		}
+		tr.Items = append(tr.Items, item)
+	}
+	tr.Items = llm.FinishItems(tr.Items)
+
+	if len(tr.Items) == 0 {
+		return nil, ErrNoItems
+	}
+	return tr, nil
+}
+
+// readReceiptField fills the totals, payment method, tax ID or receipt
+// number found on line and reports whether the line held one of them.
+// catchAllCategories name the "other" category of a caller's list.
+var catchAllCategories = map[string]bool{
+	"other": true, "others": true, "misc": true, "miscellaneous": true,
+	"general": true, "uncategorized": true, "อื่น": true, "อื่นๆ": true, "ทั่วไป": true,
+}
+
+// fallbackCategory is the category given to every item, since the parser
+// cannot tell products apart. Like the model, it keeps to the caller's list:
+// llm.DefaultCategory when the list has it or there is no list, else the
+// list's catch-all, else its first entry.
+func fallbackCategory(categories []string) string {
+	if len(categories) == 0 {
+		return llm.DefaultCategory
+	}
+	for _, c := range categories {
+		if c == llm.DefaultCategory {
+			return c
+		}
+	}
+	for _, c := range categories {
+		if catchAllCategories[strings.ToLower(strings.TrimSpace(c))] {
+			return c
+		}
+	}
+	return categories[0]
+}
+
+func readReceiptField(tr *domain.Transaction, line string) bool {
+	if tr.MerchantTaxID == "" && taxIDLineRe.MatchString(line) {
+		if id := taxIDRe.FindString(line); id != "" {
+			tr.MerchantTaxID = strings.NewReplacer(" ", "", "-", "").Replace(id)
+			return true
+		}
+	}
+	if tr.ReceiptNumber == "" {
+		if m := receiptNoRe.FindStringSubmatch(line); m != nil && amountRe.FindString(line) == "" {
+			tr.ReceiptNumber = m[1]
+			return true
+		}
+	}
+	for _, p := range paymentRes {
+		if p.re.MatchString(line) {
+			if tr.PaymentMethod == "" {
+				tr.PaymentMethod = p.method
+			}
+			return true // the amount is what was handed over, not a total
+		}
+	}
+
+	rate := percentRe.FindStringSubmatch(line)
+	amount, ok := lastAmount(percentRe.ReplaceAllString(line, ""))
+	switch {
+	case vatExclRe.MatchString(line):
+		if ok && tr.Subtotal == nil {
+			tr.Subtotal = &amount
+		}
+	case vatInclRe.MatchString(line):
+		if ok {
+			tr.GrandTotal = &amount
+		}
+	case vatRe.MatchString(line):
+		if rate != nil {
+			if r, err := strconv.ParseFloat(rate[1], 64); err == nil {
+				tr.VATRate = &r
+			}
+		}
+		if ok {
+			tr.VATAmount = &amount
+		}
+	case discountRe.MatchString(line):
+		if ok {
+			amount = amount.Abs()
+			tr.DiscountTotal = &amount
+		}
+	case serviceRe.MatchString(line):
+		if ok {
+			tr.ServiceCharge = &amount
+		}
+	case subtotalRe.MatchString(line):
+		if ok {
+			tr.Subtotal = &amount
+		}
+	case totalRe.MatchString(line):
+		if ok {
+			tr.GrandTotal = &amount // the last total line is the final one
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// lastAmount returns the last decimal amount on the line.
+func lastAmount(line string) (domain.Amount, bool) {
+	all := amountRe.FindAllString(line, -1)
+	if len(all) == 0 {
+		return domain.Amount{}, false
+	}
+	v, err := domain.ParseAmount(all[len(all)-1])
+	return v, err == nil
+}
@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+var bangkok = time.FixedZone("ICT", 7*60*60)
+
+// amountString formats an optional amount, "" when unset.
+func amountString(a *domain.Amount) string {
+	if a == nil {
+		return ""
+	}
+	return a.String()
+}
+
+func TestParseReceipts(t *testing.T) {
+	type item struct {
+		title string
+		price string
+		qty   float64
+	}
+	tests := []struct {
+		name     string
+		text     string
+		title    string
+		date     string
+		items    []item
+		subtotal string
+		discount string
+		vat      string
+		vatRate  float64
+		total    string
+		payment  string
+		taxID    string
+		receipt  string
+	}{
+		{
+			name: "Thai convenience store",
+			text: "7-ELEVEN\nสาขา 01234\nTAX ID 0107542000011\nใบเสร็จรับเงิน เลขที่ R0012345\n" +
+				"15/03/2567 14:22\nน้ำดื่ม 10.00\nขนมปัง 25.00\nกาแฟเย็น 2@35.00 70.00\nรวม 3 รายการ\n" +
+				"ยอดสุทธิ 105.00\nมูลค่าก่อนภาษี 98.13\nภาษีมูลค่าเพิ่ม 7% 6.87\nเงินสด 200.00\nทอน 95.00",
+			title:    "7-ELEVEN",
+			date:     "2024-03-15T14:22:00+07:00",
+			items:    []item{{"น้ำดื่ม", "10.00", 1}, {"ขนมปัง", "25.00", 1}, {"กาแฟเย็น", "70.00", 2}},
+			subtotal: "98.13",
+			vat:      "6.87",
+			vatRate:  7,
+			total:    "105.00",
+			payment:  domain.PaymentCash,
+			taxID:    "0107542000011",
+			receipt:  "R0012345",
+		},
+		{
+			name: "English cafe with VAT on top",
+			text: "CAFE AMAZON\nReceipt No: INV-0042\nDate: 15/03/2024\nLatte 65.00\nCroissant 55.00\n" +
+				"Subtotal 120.00\nDiscount -20.00\nTotal before VAT 100.00\nVAT 7% 7.00\n" +
+				"Total incl. VAT 107.00\nVISA **** 1234 107.00",
+			title:    "CAFE AMAZON",
+			date:     "2024-03-15",
+			items:    []item{{"Latte", "65.00", 1}, {"Croissant", "55.00", 1}},
+			subtotal: "120.00",
+			discount: "20.00",
+			vat:      "7.00",
+			vatRate:  7,
+			total:    "107.00",
+			payment:  domain.PaymentCard,
+			receipt:  "INV-0042",
+		},
+		{
+			name: "VAT base lines are not the VAT",
+			text: "SHOP\nWidget 93.46\nVAT 6.54\nPrice excl. VAT 93.46\nVatable 93.46\n" +
+				"Grand Total 100.00\nPromptPay 100.00",
+			title:    "SHOP",
+			items:    []item{{"Widget", "93.46", 1}},
+			subtotal: "93.46",
+			vat:      "6.54",
+			total:    "100.00",
+			payment:  domain.PaymentQR,
+		},
+		{
+			name: "Thai restaurant with service charge",
+			text: "ร้านอาหารครัวไทย\nผัดกะเพรา 60.00\nต้มยำกุ้ง 150.00\nค่าบริการ 10% 21.00\n" +
+				"ยอดก่อนภาษี 231.00\nภาษีมูลค่าเพิ่ม 16.17\nรวมทั้งสิ้น 247.17\nพร้อมเพย์",
+			title:    "ร้านอาหารครัวไทย",
+			items:    []item{{"ผัดกะเพรา", "60.00", 1}, {"ต้มยำกุ้ง", "150.00", 1}},
+			subtotal: "231.00",
+			vat:      "16.17",
+			total:    "247.17",
+			payment:  domain.PaymentQR,
+		},
+		{
+			name:  "name and price on separate lines",
+			text:  "MINI MART\nFRESH MILK 1L\n45.00\nTotal 45.00",
+			title: "MINI MART",
+			items: []item{{"FRESH MILK 1L", "45.00", 1}},
+			total: "45.00",
+		},
+	}
+	p := NewParser(bangkok)
+	for _, tt := range tests {
+		tr, err := p.ParseOcrResponseToJson(context.Background(), tt.text, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tr.Source != domain.SourceRules {
+			t.Errorf("%s: source = %q, want %q", tt.name, tr.Source, domain.SourceRules)
+		}
+		if tr.Title != tt.title || tr.Date != tt.date {
+			t.Errorf("%s: title, date = %q, %q; want %q, %q", tt.name, tr.Title, tr.Date, tt.title, tt.date)
+		}
+		var got []item
+		for _, it := range tr.Items {
+			got = append(got, item{it.Title, it.Price.String(), it.Quantity})
+		}
+		if !reflect.DeepEqual(got, tt.items) {
+			t.Errorf("%s: items = %v, want %v", tt.name, got, tt.items)
+		}
+		for _, f := range []struct {
+			field     string
+			got, want string
+		}{
+			{"subtotal", amountString(tr.Subtotal), tt.subtotal},
+			{"discount_total", amountString(tr.DiscountTotal), tt.discount},
+			{"vat_amount", amountString(tr.VATAmount), tt.vat},
+			{"grand_total", amountString(tr.GrandTotal), tt.total},
+			{"payment_method", tr.PaymentMethod, tt.payment},
+			{"merchant_tax_id", tr.MerchantTaxID, tt.taxID},
+			{"receipt_number", tr.ReceiptNumber, tt.receipt},
+		} {
+			if f.got != f.want {
+				t.Errorf("%s: %s = %q, want %q", tt.name, f.field, f.got, f.want)
+			}
+		}
+		var rate float64
+		if tr.VATRate != nil {
+			rate = *tr.VATRate
+		}
+		if rate != tt.vatRate {
+			t.Errorf("%s: vat_rate = %v, want %v", tt.name, rate, tt.vatRate)
+		}
+	}
+}
+
+func TestParseWithoutItems(t *testing.T) {
+	p := NewParser(bangkok)
+	_, err := p.ParseOcrResponseToJson(context.Background(), "THANK YOU\nTotal 0.00", nil)
+	if !errors.Is(err, ErrNoItems) || !errors.Is(err, domain.ErrInvalidOutput) {
+		t.Errorf("err = %v, want ErrNoItems", err)
+	}
+}
+
+func TestItemCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		want       string
+	}{
+		{"no list", nil, "อื่นๆ"},
+		{"default in the list", []string{"อาหาร", "อื่นๆ"}, "อื่นๆ"},
+		{"catch-all in the list", []string{"Food", "Transport", "Other"}, "Other"},
+		{"Thai catch-all", []string{"อาหาร", "อื่น"}, "อื่น"},
+		{"no catch-all", []string{"Food", "Transport"}, "Food"},
+	}
+	p := NewParser(bangkok)
+	for _, tt := range tests {
+		tr, err := p.ParseOcrResponseToJson(context.Background(), "SHOP\nCOKE 35.00\nBREAD 25.00", tt.categories)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for _, it := range tr.Items {
+			if it.Category != tt.want {
+				t.Errorf("%s: %s category = %q, want %q", tt.name, it.Title, it.Category, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "regexp"
+
+// DefaultCurrency is assumed when neither the text nor the model names one.
+const DefaultCurrency = "THB"
+
+// currencyExponents are the ISO 4217 minor unit exponents of the currencies
+// we expect on receipts.
+var currencyExponents = map[string]int{
+	"THB": 2, "USD": 2, "EUR": 2, "GBP": 2, "SGD": 2, "MYR": 2, "CNY": 2,
+	"HKD": 2, "LAK": 2, "MMK": 2, "KHR": 2, "JPY": 0, "KRW": 0, "VND": 0,
+}
+
+// CurrencyExponent returns the number of minor unit digits of an ISO 4217
+// code; ok is false for codes we do not know.
+func CurrencyExponent(code string) (exp int, ok bool) {
+	exp, ok = currencyExponents[code]
+	return exp, ok
+}
+
+// currencySigns are the symbols and words that give a receipt's currency
+// away. More specific signs come first: "S$" and "US$" before "$".
+var currencySigns = []struct {
+	code string
+	re   *regexp.Regexp
+}{
+	{"THB", regexp.MustCompile(`(?i)฿|บาท|\bTHB\b|\bbaht\b`)},
+	{"SGD", regexp.MustCompile(`(?i)\bS\$|\bSGD\b`)},
+	{"MYR", regexp.MustCompile(`(?i)\bRM\s?\d|\bMYR\b|\bringgit\b`)},
+	{"CNY", regexp.MustCompile(`(?i)\bCNY\b|\bRMB\b|元|人民币`)},
+	{"JPY", regexp.MustCompile(`(?i)¥|\bJPY\b|円`)},
+	{"HKD", regexp.MustCompile(`(?i)\bHK\$|\bHKD\b`)},
+	{"USD", regexp.MustCompile(`(?i)US\$|\bUSD\b|\$`)},
+	{"EUR", regexp.MustCompile(`(?i)€|\bEUR\b`)},
+	{"GBP", regexp.MustCompile(`(?i)£|\bGBP\b`)},
+	{"KRW", regexp.MustCompile(`(?i)₩|\bKRW\b`)},
+	{"VND", regexp.MustCompile(`(?i)₫|\bVND\b`)},
+	{"LAK", regexp.MustCompile(`(?i)₭|\bLAK\b|\bkip\b`)},
+}
+
+// DetectCurrency returns the ISO 4217 code whose signs occur most often in
+// text, or "" when there are none. "¥" counts as yuan when the text also
+// says 元 or RMB, and "$" is not counted again when "S$", "US$" or "HK$"
+// already matched.
+func DetectCurrency(text string) string {
+	best, bestN := "", 0
+	counts := map[string]int{}
+	for _, c := range currencySigns {
+		counts[c.code] = len(c.re.FindAllStringIndex(text, -1))
+	}
+	if counts["CNY"] > 0 {
+		counts["CNY"] += counts["JPY"]
+		counts["JPY"] = 0
+	}
+	counts["USD"] -= counts["SGD"] + counts["HKD"]
+	for _, c := range currencySigns {
+		if n := counts[c.code]; n > bestN {
+			best, bestN = c.code, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "testing"
+
+func TestDetectCurrency(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"TOTAL ฿85.00", "THB"},
+		{"รวม 85 บาท", "THB"},
+		{"Total: 12.50 THB", "THB"},
+		{"coffee 85", ""},
+		{"TOTAL S$12.50", "SGD"},
+		{"TOTAL US$12.50", "USD"},
+		{"TOTAL $12.50", "USD"},
+		{"HK$ 40 HK$ 12", "HKD"},
+		{"RM12.90", "MYR"},
+		{"合计 ¥35 元", "CNY"},
+		{"合計 ¥1,200", "JPY"},
+		{"€4.20", "EUR"},
+		{"£3", "GBP"},
+		{"₩12,000", "KRW"},
+		{"$5 tip, ฿100 and 20 บาท", "THB"},
+	}
+	for _, tt := range tests {
+		if got := DetectCurrency(tt.text); got != tt.want {
+			t.Errorf("DetectCurrency(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		code string
+		exp  int
+		ok   bool
+	}{
+		{"THB", 2, true},
+		{"JPY", 0, true},
+		{"VND", 0, true},
+		{"thb", 0, false},
+		{"XXX", 0, false},
+	}
+	for _, tt := range tests {
+		exp, ok := CurrencyExponent(tt.code)
+		if exp != tt.exp || ok != tt.ok {
+			t.Errorf("CurrencyExponent(%q) = %d, %v, want %d, %v", tt.code, exp, ok, tt.exp, tt.ok)
+		}
+	}
+}
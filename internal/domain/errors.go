@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Sentinel errors adapters match (via an Is method) so the usecase layer can
+// classify upstream failures without knowing adapter types.
+var (
+	ErrUnavailable   = errors.New("upstream unavailable")
+	ErrRateLimited   = errors.New("upstream rate limited")
+	ErrInvalidInput  = errors.New("input rejected by upstream")
+	ErrInvalidOutput = errors.New("invalid model output")
+	// ErrOverloaded marks requests shed by our own admission control.
+	ErrOverloaded = errors.New("service overloaded")
+)
+
+// RetryDelayer is implemented by errors that know how long the caller should
+// wait before retrying.
+type RetryDelayer interface {
+	RetryDelay() time.Duration
+}
+
+// InvalidFielder is implemented by ErrInvalidInput errors that know which
+// request field was rejected.
+type InvalidFielder interface {
+	InvalidField() string
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "fmt"
+
+// Machine-readable reasons an image fails the quality gate.
+const (
+	QualityTooBlurry = "TOO_BLURRY"
+	QualityTooDark   = "TOO_DARK"
+	QualityTooBright = "TOO_BRIGHT"
+	QualityTooSmall  = "TOO_SMALL"
+	QualityBadAspect = "BAD_ASPECT_RATIO"
+)
+
+// QualityError rejects a photo that would not OCR well, so the user can be
+// asked to retake it instead of getting an empty or invented transaction.
+type QualityError struct {
+	Reason    string // one of the Quality* constants
+	Metric    string
+	Value     float64
+	Threshold float64
+}
+
+func (e *QualityError) Error() string {
+	return fmt.Sprintf("image rejected (%s): %s %.1f, threshold %.1f", e.Reason, e.Metric, e.Value, e.Threshold)
+}
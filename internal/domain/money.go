@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact decimal, Minor x 10^-Exp. Once a transaction's currency
+// is known its amounts are kept at the currency's exponent, so Minor is the
+// amount in minor units (satang for THB). In JSON it is a plain number.
+type Amount struct {
+	Minor int64
+	Exp   int
+}
+
+// maxExp bounds the precision kept from parsed amounts.
+const maxExp = 6
+
+var pow10 = [...]int64{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000}
+
+// ParseAmount reads an amount as printed on a receipt or written by the
+// model: "1,234.50", "1.234,50", "฿85", "85 บาท", "-10.00". A lone comma
+// followed by exactly three digits ("1,234") is a thousands separator; a
+// lone dot is always a decimal point, so "1.000" is one. A minus sign before
+// or after the number ("฿-10", "10.00-") or parentheses ("(10.00)") make it
+// negative; a dash between digits ("10-20") does not.
+func ParseAmount(s string) (Amount, error) {
+	neg := isNegative(s)
+	clean := nonAmountRe.ReplaceAllString(s, "")
+	if clean == "" || !strings.ContainsAny(clean, "0123456789") {
+		return Amount{}, fmt.Errorf("not an amount: %q", s)
+	}
+
+	// the last separator is the decimal point unless it groups thousands
+	intPart, frac := clean, ""
+	if i := strings.LastIndexAny(clean, ".,"); i >= 0 {
+		sep, otherSep := clean[i], byte(',')
+		if sep == ',' {
+			otherSep = '.'
+		}
+		grouped := strings.Count(clean, string(sep)) > 1 || (sep == ',' && len(clean)-i-1 == 3)
+		if strings.IndexByte(clean[:i], otherSep) >= 0 || !grouped {
+			intPart, frac = clean[:i], clean[i+1:]
+		}
+	}
+	intPart = strings.NewReplacer(".", "", ",", "").Replace(intPart)
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	exp := len(frac)
+	digits := intPart + frac
+	if exp > maxExp {
+		// keep one extra digit to round from
+		digits, exp = digits[:len(digits)-(exp-maxExp-1)], maxExp+1
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("not an amount: %q", s)
+	}
+	if neg {
+		minor = -minor
+	}
+	a := Amount{Minor: minor, Exp: exp}
+	if exp > maxExp {
+		a = a.Rescale(maxExp)
+	}
+	return a, nil
+}
+
+var nonAmountRe = regexp.MustCompile(`[^0-9.,]`)
+
+// isNegative reports whether the text around the digits of s marks it as
+// negative: a minus sign at either end of it, or parentheses around it.
+func isNegative(s string) bool {
+	first, last := strings.IndexAny(s, "0123456789"), strings.LastIndexAny(s, "0123456789")
+	if first < 0 {
+		return false
+	}
+	before, after := strings.TrimSpace(s[:first]), strings.TrimSpace(s[last+1:])
+	for _, side := range []string{before, after} {
+		for _, minus := range []string{"-", "−"} {
+			if strings.HasPrefix(side, minus) || strings.HasSuffix(side, minus) {
+				return true
+			}
+		}
+	}
+	return strings.Contains(before, "(") && strings.Contains(after, ")")
+}
+
+// AmountFromFloat converts f at exp decimal places.
+func AmountFromFloat(f float64, exp int) Amount {
+	return Amount{Minor: int64(math.Round(f * float64(pow10[exp]))), Exp: exp}
+}
+
+func (a Amount) Float64() float64 { return float64(a.Minor) / float64(pow10[a.Exp]) }
+
+func (a Amount) IsZero() bool { return a.Minor == 0 }
+
+// Sign returns -1, 0 or +1.
+func (a Amount) Sign() int {
+	switch {
+	case a.Minor < 0:
+		return -1
+	case a.Minor > 0:
+		return 1
+	}
+	return 0
+}
+
+func (a Amount) Abs() Amount {
+	if a.Minor < 0 {
+		a.Minor = -a.Minor
+	}
+	return a
+}
+
+// Rescale returns a at exp decimal places, rounding half away from zero.
+func (a Amount) Rescale(exp int) Amount {
+	switch {
+	case exp == a.Exp:
+		return a
+	case exp > a.Exp:
+		return Amount{Minor: a.Minor * pow10[exp-a.Exp], Exp: exp}
+	}
+	div := pow10[a.Exp-exp]
+	q, r := a.Minor/div, a.Minor%div
+	if 2*abs64(r) >= div {
+		if a.Minor < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount{Minor: q, Exp: exp}
+}
+
+// Cmp compares a and b exactly: -1 when a < b, 0 when equal, +1 when a > b.
+func (a Amount) Cmp(b Amount) int {
+	return a.Sub(b).Sign()
+}
+
+// Add returns a+b exactly, at the larger of the two exponents.
+func (a Amount) Add(b Amount) Amount {
+	exp := max(a.Exp, b.Exp)
+	return Amount{Minor: a.Rescale(exp).Minor + b.Rescale(exp).Minor, Exp: exp}
+}
+
+// Sub returns a-b exactly, at the larger of the two exponents.
+func (a Amount) Sub(b Amount) Amount {
+	b.Minor = -b.Minor
+	return a.Add(b)
+}
+
+// Mul returns a times a quantity (which may be a weight), rounded to a's
+// exponent.
+func (a Amount) Mul(q float64) Amount {
+	return Amount{Minor: int64(math.Round(float64(a.Minor) * q)), Exp: a.Exp}
+}
+
+// Div returns a divided by a quantity, rounded to a's exponent.
+func (a Amount) Div(q float64) Amount {
+	return Amount{Minor: int64(math.Round(float64(a.Minor) / q)), Exp: a.Exp}
+}
+
+// String formats a with exactly Exp decimals: "1234.50".
+func (a Amount) String() string {
+	s := strconv.FormatInt(abs64(a.Minor), 10)
+	if a.Exp > 0 {
+		if len(s) <= a.Exp {
+			s = strings.Repeat("0", a.Exp-len(s)+1) + s
+		}
+		s = s[:len(s)-a.Exp] + "." + s[len(s)-a.Exp:]
+	}
+	if a.Minor < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) { return []byte(a.String()), nil }
+
+// UnmarshalJSON accepts a number or a string such as "1,234.50".
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	var s string
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(b)
+		if strings.ContainsAny(s, "eE") {
+			// exponent notation, not worth an exact path
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return err
+			}
+			*a = AmountFromFloat(f, 2)
+			return nil
+		}
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"1,234.50", Amount{Minor: 123450, Exp: 2}},
+		{"1.234,50", Amount{Minor: 123450, Exp: 2}},
+		{"฿85", Amount{Minor: 85}},
+		{"85 บาท", Amount{Minor: 85}},
+		{"-10.00", Amount{Minor: -1000, Exp: 2}},
+		{"1,234", Amount{Minor: 1234}},
+		{"1,000,000", Amount{Minor: 1000000}},
+		{"1.000.000", Amount{Minor: 1000000}},
+		{"1.000", Amount{Minor: 1000, Exp: 3}},
+		{"12,5", Amount{Minor: 125, Exp: 1}},
+		{".50", Amount{Minor: 50, Exp: 2}},
+		{"0.12345678", Amount{Minor: 123457, Exp: 6}},
+		{"฿-85", Amount{Minor: -85}},
+		{"-฿85", Amount{Minor: -85}},
+		{"10.00-", Amount{Minor: -1000, Exp: 2}},
+		{"(10.00)", Amount{Minor: -1000, Exp: 2}},
+		{"−10", Amount{Minor: -10}},
+		// a dash inside the number does not make it negative
+		{"10-20", Amount{Minor: 1020}},
+		{"2024-03-15", Amount{Minor: 20240315}},
+		{"Item-A 10", Amount{Minor: 10}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountRejects(t *testing.T) {
+	for _, in := range []string{"", "baht", ".,", "-"} {
+		if got, err := ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestAmountRescale(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		exp  int
+		want Amount
+	}{
+		{Amount{Minor: 85}, 2, Amount{Minor: 8500, Exp: 2}},
+		{Amount{Minor: 12345, Exp: 3}, 2, Amount{Minor: 1235, Exp: 2}},
+		{Amount{Minor: -12345, Exp: 3}, 2, Amount{Minor: -1235, Exp: 2}},
+		{Amount{Minor: 12344, Exp: 3}, 2, Amount{Minor: 1234, Exp: 2}},
+		{Amount{Minor: 150, Exp: 2}, 0, Amount{Minor: 2}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Rescale(tt.exp); got != tt.want {
+			t.Errorf("%+v.Rescale(%d) = %+v, want %+v", tt.a, tt.exp, got, tt.want)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		want string
+	}{
+		{Amount{Minor: 123450, Exp: 2}, "1234.50"},
+		{Amount{Minor: 5, Exp: 2}, "0.05"},
+		{Amount{Minor: -5, Exp: 2}, "-0.05"},
+		{Amount{Minor: 85}, "85"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		out  string
+	}{
+		{`1234.5`, Amount{Minor: 12345, Exp: 1}, `1234.5`},
+		{`"1,234.50"`, Amount{Minor: 123450, Exp: 2}, `1234.50`},
+		{`85`, Amount{Minor: 85}, `85`},
+		{`1e2`, Amount{Minor: 10000, Exp: 2}, `100.00`},
+	}
+	for _, tt := range tests {
+		var a Amount
+		if err := json.Unmarshal([]byte(tt.in), &a); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if a != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.in, a, tt.want)
+		}
+		out, err := json.Marshal(a)
+		if err != nil {
+			t.Errorf("Marshal(%+v): %v", a, err)
+			continue
+		}
+		if string(out) != tt.out {
+			t.Errorf("Marshal(%+v) = %s, want %s", a, out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PageFailure describes a document page the OCR provider could not read.
+type PageFailure struct {
+	Page   int    `json:"page"`
+	Reason string `json:"reason"`
+}
+
+// PartialOCRError is returned together with the text of the pages that did
+// succeed when only some pages of a multi-page document failed.
+type PartialOCRError struct {
+	TotalPages int
+	Failed     []PageFailure
+}
+
+func (e *PartialOCRError) Error() string {
+	pages := make([]string, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		pages = append(pages, fmt.Sprintf("%d (%s)", f.Page, f.Reason))
+	}
+	return fmt.Sprintf("OCR failed on %d of %d pages: %s", len(e.Failed), e.TotalPages, strings.Join(pages, ", "))
+}
+
+// FailedPageNumbers returns the failed page numbers in order.
+func (e *PartialOCRError) FailedPageNumbers() []int {
+	nums := make([]int, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		nums = append(nums, f.Page)
+	}
+	return nums
+}
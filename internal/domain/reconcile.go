@@ -0,0 +1,137 @@
+package domain
+
+import "fmt"
+
+// ReconcileTolerance is how far the item sum may be off the receipt total
+// in currency and still count as consistent: a quarter of the major unit
+// (satang rounding of cash totals and VAT), and at least one minor unit.
+// Unknown currencies are taken to have 2 decimals.
+func ReconcileTolerance(currency string) Amount {
+	exp, ok := CurrencyExponent(currency)
+	if !ok {
+		exp = 2
+	}
+	tol := Amount{Minor: 25, Exp: 2}.Rescale(exp)
+	if tol.IsZero() {
+		tol = Amount{Minor: 1, Exp: exp}
+	}
+	return tol
+}
+
+// Why an item is suspected of causing a mismatch.
+const (
+	SuspectMatchesDiscrepancy = "MATCHES_DISCREPANCY" // removing it would fix the sum
+	SuspectExceedsTotal       = "EXCEEDS_TOTAL"       // more than the whole receipt, likely a product code
+	SuspectQuantity           = "QUANTITY"            // the gap is a multiple of its price
+)
+
+// Reconciliation compares the sum of the item prices with what the receipt
+// says the items should add up to.
+type Reconciliation struct {
+	Consistent    bool
+	ItemsTotal    Amount
+	ExpectedTotal Amount
+	// Discrepancy is ItemsTotal - ExpectedTotal: positive when the items add
+	// up to more than the receipt, negative when something is missing.
+	Discrepancy Amount
+	Suspects    []SuspectItem
+}
+
+type SuspectItem struct {
+	Index  int // into Transaction.Items
+	Reason string
+}
+
+func (r *Reconciliation) String() string {
+	if r.Consistent {
+		return fmt.Sprintf("items add up to %s as expected", r.ItemsTotal)
+	}
+	return fmt.Sprintf("items add up to %s, receipt says %s (off by %s)", r.ItemsTotal, r.ExpectedTotal, r.Discrepancy)
+}
+
+// Reconcile checks the items against the grand total (or subtotal) and the
+// discount, service charge and VAT printed on the receipt. It returns nil
+// when the receipt shows no total to check against.
+func Reconcile(t *Transaction) *Reconciliation {
+	var sum Amount
+	for _, item := range t.Items {
+		sum = sum.Add(item.Price)
+	}
+
+	// the items can add up to any of these, depending on whether discounts
+	// and VAT are printed before or after the total
+	discount, service, vat := value(t.DiscountTotal), value(t.ServiceCharge), value(t.VATAmount)
+	var candidates []Amount
+	if t.GrandTotal != nil {
+		base := t.GrandTotal.Add(discount).Sub(service)
+		candidates = append(candidates, base)
+		if vat.Sign() > 0 {
+			candidates = append(candidates, base.Sub(vat)) // VAT added on top
+		}
+	}
+	if t.Subtotal != nil {
+		candidates = append(candidates, *t.Subtotal)
+		if discount.Sign() > 0 {
+			candidates = append(candidates, t.Subtotal.Add(discount))
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	expected := candidates[0]
+	for _, c := range candidates[1:] {
+		if sum.Sub(c).Abs().Cmp(sum.Sub(expected).Abs()) < 0 {
+			expected = c
+		}
+	}
+	r := &Reconciliation{
+		ItemsTotal:    sum,
+		ExpectedTotal: expected,
+		Discrepancy:   sum.Sub(expected),
+	}
+	tol := ReconcileTolerance(t.Currency)
+	r.Consistent = r.Discrepancy.Abs().Cmp(tol) <= 0
+	if !r.Consistent {
+		r.Suspects = suspects(t.Items, r, tol)
+	}
+	return r
+}
+
+// suspects points at the items that most likely explain the discrepancy.
+// A dropped item cannot be pointed at, so a shortfall may have none.
+func suspects(items []TransactionItem, r *Reconciliation, tol Amount) []SuspectItem {
+	d, expected := r.Discrepancy, r.ExpectedTotal
+	var out []SuspectItem
+	for i, item := range items {
+		price := item.Price
+		switch {
+		case d.Sign() > 0 && expected.Sign() > 0 && price.Cmp(expected.Add(tol)) > 0:
+			out = append(out, SuspectItem{Index: i, Reason: SuspectExceedsTotal})
+		case d.Sign() > 0 && price.Sub(d).Abs().Cmp(tol) <= 0:
+			out = append(out, SuspectItem{Index: i, Reason: SuspectMatchesDiscrepancy})
+		case d.Sign() < 0 && price.Sign() > 0 && isMultiple(d.Abs(), price, tol):
+			// a quantity line merged into the wrong item, or not at all
+			out = append(out, SuspectItem{Index: i, Reason: SuspectQuantity})
+		}
+	}
+	return out
+}
+
+// isMultiple reports whether gap is 1 to 20 times price, give or take tol.
+func isMultiple(gap, price, tol Amount) bool {
+	exp := max(gap.Exp, price.Exp, tol.Exp)
+	g, p := gap.Rescale(exp).Minor, price.Rescale(exp).Minor
+	if g < p || g > 20*p {
+		return false
+	}
+	k := (g + p/2) / p
+	return abs64(g-k*p) <= tol.Rescale(exp).Minor
+}
+
+func value(p *Amount) Amount {
+	if p == nil {
+		return Amount{}
+	}
+	return *p
+}
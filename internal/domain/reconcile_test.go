@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func amt(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func amtp(s string) *Amount {
+	a := amt(s)
+	return &a
+}
+
+func items(prices ...string) []TransactionItem {
+	var out []TransactionItem
+	for _, p := range prices {
+		out = append(out, TransactionItem{Title: "item", Price: amt(p), Quantity: 1})
+	}
+	return out
+}
+
+func TestReconcileTolerance(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     Amount
+	}{
+		{"THB", Amount{Minor: 25, Exp: 2}},
+		{"USD", Amount{Minor: 25, Exp: 2}},
+		{"JPY", Amount{Minor: 1}},
+		{"", Amount{Minor: 25, Exp: 2}},
+		{"XYZ", Amount{Minor: 25, Exp: 2}},
+	}
+	for _, tt := range tests {
+		if got := ReconcileTolerance(tt.currency); got != tt.want {
+			t.Errorf("ReconcileTolerance(%q) = %+v, want %+v", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		tr          Transaction
+		consistent  bool
+		discrepancy string
+		suspects    []SuspectItem
+	}{
+		{
+			name:        "consistent",
+			tr:          Transaction{Currency: "THB", Items: items("35.00", "65.00"), GrandTotal: amtp("100.00")},
+			consistent:  true,
+			discrepancy: "0.00",
+		},
+		{
+			name:        "within a quarter baht",
+			tr:          Transaction{Currency: "THB", Items: items("35.13", "65.00"), GrandTotal: amtp("100.00")},
+			consistent:  true,
+			discrepancy: "0.13",
+		},
+		{
+			name:        "just past a quarter baht",
+			tr:          Transaction{Currency: "THB", Items: items("35.26", "65.00"), GrandTotal: amtp("100.00")},
+			discrepancy: "0.26",
+		},
+		{
+			name:        "item matches the discrepancy",
+			tr:          Transaction{Currency: "THB", Items: items("35.00", "65.00", "100.00"), GrandTotal: amtp("100.00")},
+			discrepancy: "100.00",
+			suspects:    []SuspectItem{{Index: 2, Reason: SuspectMatchesDiscrepancy}},
+		},
+		{
+			name:        "product code read as a price",
+			tr:          Transaction{Currency: "THB", Items: items("35.00", "8850123.00", "65.00"), GrandTotal: amtp("100.00")},
+			discrepancy: "8850123.00",
+			suspects:    []SuspectItem{{Index: 1, Reason: SuspectExceedsTotal}},
+		},
+		{
+			name:        "quantity not applied",
+			tr:          Transaction{Currency: "THB", Items: items("20.00", "50.00"), GrandTotal: amtp("130.00")},
+			discrepancy: "-60.00",
+			suspects:    []SuspectItem{{Index: 0, Reason: SuspectQuantity}},
+		},
+		{
+			name: "discount taken off the grand total",
+			tr: Transaction{Currency: "THB", Items: items("60.00", "60.00"),
+				DiscountTotal: amtp("20.00"), GrandTotal: amtp("100.00")},
+			consistent:  true,
+			discrepancy: "0.00",
+		},
+		{
+			name: "service charge and VAT added on top",
+			tr: Transaction{Currency: "THB", Items: items("100.00", "100.00"),
+				ServiceCharge: amtp("20.00"), VATAmount: amtp("15.40"), GrandTotal: amtp("235.40")},
+			consistent:  true,
+			discrepancy: "0.00",
+		},
+		{
+			name: "VAT included in the prices",
+			tr: Transaction{Currency: "THB", Items: items("53.50", "53.50"),
+				VATAmount: amtp("7.00"), GrandTotal: amtp("107.00")},
+			consistent:  true,
+			discrepancy: "0.00",
+		},
+		{
+			name: "subtotal before the discount",
+			tr: Transaction{Currency: "THB", Items: items("80.00", "40.00"),
+				Subtotal: amtp("100.00"), DiscountTotal: amtp("20.00")},
+			consistent:  true,
+			discrepancy: "0.00",
+		},
+		{
+			name:        "yen off by one is consistent",
+			tr:          Transaction{Currency: "JPY", Items: items("301", "200"), GrandTotal: amtp("500")},
+			consistent:  true,
+			discrepancy: "1",
+		},
+		{
+			name:        "yen off by two is not",
+			tr:          Transaction{Currency: "JPY", Items: items("302", "200"), GrandTotal: amtp("500")},
+			discrepancy: "2",
+		},
+	}
+	for _, tt := range tests {
+		r := Reconcile(&tt.tr)
+		if r == nil {
+			t.Errorf("%s: Reconcile = nil", tt.name)
+			continue
+		}
+		if r.Consistent != tt.consistent || r.Discrepancy.String() != tt.discrepancy {
+			t.Errorf("%s: Reconcile = consistent %v, discrepancy %s; want %v, %s",
+				tt.name, r.Consistent, r.Discrepancy, tt.consistent, tt.discrepancy)
+		}
+		if !reflect.DeepEqual(r.Suspects, tt.suspects) {
+			t.Errorf("%s: suspects = %+v, want %+v", tt.name, r.Suspects, tt.suspects)
+		}
+	}
+}
+
+func TestReconcileWithoutTotal(t *testing.T) {
+	tr := Transaction{Currency: "THB", Items: items("35.00")}
+	if r := Reconcile(&tr); r != nil {
+		t.Errorf("Reconcile = %v, want nil without a total", r)
+	}
+}
@@ -1,13 +1,108 @@
 package domain
 
+import "slices"
+
+// Sources a Transaction can come from.
+const (
+	SourceLLM   = "llm"
+	SourceRules = "rules" // the deterministic fallback parser
+)
+
+// Payment methods a receipt can show; empty means not printed or unknown.
+const (
+	PaymentCash    = "cash"
+	PaymentCard    = "card"
+	PaymentQR      = "qr" // PromptPay and other QR payments
+	PaymentEWallet = "e_wallet"
+)
+
+// PaymentMethods lists the accepted Transaction.PaymentMethod values.
+var PaymentMethods = []string{PaymentCash, PaymentCard, PaymentQR, PaymentEWallet}
+
 type Transaction struct {
 	Title string            `json:"title"`
 	Date  string            `json:"date"` // normalized as YYYY-MM-DDTHH:MM:SS(+TZ)
 	Items []TransactionItem `json:"items"`
+
+	// Currency is the ISO 4217 code all amounts are in.
+	Currency string `json:"currency"`
+
+	// Receipt totals as printed; nil when the receipt does not show them.
+	Subtotal      *Amount  `json:"subtotal"`
+	DiscountTotal *Amount  `json:"discount_total"` // positive amount taken off
+	ServiceCharge *Amount  `json:"service_charge"`
+	VATAmount     *Amount  `json:"vat_amount"`
+	VATRate       *float64 `json:"vat_rate"` // percent, e.g. 7
+	GrandTotal    *Amount  `json:"grand_total"`
+
+	PaymentMethod string `json:"payment_method"` // one of PaymentMethods or empty
+	MerchantTaxID string `json:"merchant_tax_id"`
+	ReceiptNumber string `json:"receipt_number"`
+
+	// Source is which parser produced the transaction; empty means SourceLLM.
+	// Not part of the model's JSON.
+	Source string `json:"-"`
 }
 
+// Units of measure of TransactionItem.Unit.
+const (
+	UnitPiece = "pcs"
+	UnitKg    = "kg"
+	UnitLitre = "L"
+)
+
+// Units lists the accepted TransactionItem.Unit values.
+var Units = []string{UnitPiece, UnitKg, UnitLitre}
+
 type TransactionItem struct {
-	Title    string  `json:"title"`
-	Price    float64 `json:"price"`
-	Category string     `json:"category"`
+	Title    string `json:"title"`
+	Price    Amount `json:"price"` // line total, Quantity x UnitPrice
+	Category string `json:"category"`
+
+	Quantity  float64 `json:"quantity"` // pieces, or the weight/volume in Unit
+	UnitPrice Amount  `json:"unit_price"`
+	Unit      string  `json:"unit"` // one of Units
+}
+
+// SetCurrency sets the currency and brings every amount to its exponent, so
+// their Minor values are minor units of code. Unknown codes keep 2 decimals.
+func (t *Transaction) SetCurrency(code string) {
+	exp, ok := CurrencyExponent(code)
+	if !ok {
+		exp = 2
+	}
+	t.Currency = code
+	for _, a := range []*Amount{t.Subtotal, t.DiscountTotal, t.ServiceCharge, t.VATAmount, t.GrandTotal} {
+		if a != nil {
+			*a = a.Rescale(exp)
+		}
+	}
+	for i := range t.Items {
+		t.Items[i].Price = t.Items[i].Price.Rescale(exp)
+		t.Items[i].UnitPrice = t.Items[i].UnitPrice.Rescale(exp)
+	}
+}
+
+// Clone returns a copy of t that shares no memory with it.
+func (t *Transaction) Clone() *Transaction {
+	c := *t
+	c.Items = slices.Clone(t.Items)
+	for _, p := range []**Amount{&c.Subtotal, &c.DiscountTotal, &c.ServiceCharge, &c.VATAmount, &c.GrandTotal} {
+		if *p != nil {
+			v := **p
+			*p = &v
+		}
+	}
+	if t.VATRate != nil {
+		r := *t.VATRate
+		c.VATRate = &r
+	}
+	return &c
+}
+
+// Entry is one of several transactions typed in a single message, with the
+// part of the message it was read from.
+type Entry struct {
+	Text        string
+	Transaction *Transaction
 }
@@ -0,0 +1,150 @@
+// Package admission bounds how much work runs concurrently against a scarce
+// backend (the GPU) and sheds load that could not be served in time.
+package admission
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+// RejectedError is returned when a request is shed instead of queued.
+type RejectedError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e RejectedError) Error() string             { return "overloaded: " + e.Reason }
+func (e RejectedError) Is(target error) bool      { return target == domain.ErrOverloaded }
+func (e RejectedError) RetryDelay() time.Duration { return e.RetryAfter }
+
+// Limiter allows maxInFlight concurrent holders and up to maxQueue waiters.
+// A waiter is rejected up front when its deadline is sooner than the
+// estimated queue wait plus service time, and gives up when its deadline
+// or the limiter's own maxWait passes while still queued.
+type Limiter struct {
+	slots    chan struct{}
+	maxQueue int64
+	maxWait  time.Duration
+
+	queued     atomic.Int64
+	avgService atomic.Int64 // EWMA of holding time, ns
+
+	stats *expvar.Map
+}
+
+// New creates a limiter whose gauges and counters are published in expvar
+// under name: in_flight, queue_depth, admitted, rejected_queue_full,
+// rejected_deadline, wait_ms_total, waited and avg_service_ms.
+func New(name string, maxInFlight, maxQueue int, maxWait time.Duration) *Limiter {
+	l := &Limiter{
+		slots:    make(chan struct{}, maxInFlight),
+		maxQueue: int64(maxQueue),
+		maxWait:  maxWait,
+		stats:    expvar.NewMap(name),
+	}
+	l.stats.Set("avg_service_ms", expvar.Func(func() any {
+		return time.Duration(l.avgService.Load()).Milliseconds()
+	}))
+	return l
+}
+
+// Acquire blocks until a slot is free. The returned release func must be
+// called once the work is done.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case l.slots <- struct{}{}:
+		return l.admit(0), nil
+	default:
+	}
+
+	pos := l.queued.Add(1)
+	defer func() {
+		l.queued.Add(-1)
+		l.stats.Add("queue_depth", -1)
+	}()
+	l.stats.Add("queue_depth", 1)
+
+	if pos > l.maxQueue {
+		l.stats.Add("rejected_queue_full", 1)
+		return nil, RejectedError{Reason: "queue full", RetryAfter: l.estimatedWait(pos)}
+	}
+
+	estimate := l.estimatedWait(pos)
+	if deadline, ok := ctx.Deadline(); ok && estimate > 0 {
+		// it has to wait its turn and then actually be served
+		if time.Until(deadline) < estimate+time.Duration(l.avgService.Load()) {
+			l.stats.Add("rejected_deadline", 1)
+			return nil, RejectedError{
+				Reason:     fmt.Sprintf("deadline too short for estimated queue wait of %v", estimate.Round(time.Millisecond)),
+				RetryAfter: estimate,
+			}
+		}
+	}
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	start := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+		return l.admit(time.Since(start)), nil
+	case <-timer.C:
+		l.stats.Add("rejected_deadline", 1)
+		return nil, RejectedError{Reason: "queue wait exceeded " + l.maxWait.String(), RetryAfter: estimate}
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			l.stats.Add("rejected_deadline", 1)
+			return nil, RejectedError{Reason: "deadline reached while queued", RetryAfter: estimate}
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Limiter) admit(waited time.Duration) func() {
+	l.stats.Add("admitted", 1)
+	l.stats.Add("in_flight", 1)
+	if waited > 0 {
+		l.stats.Add("waited", 1)
+		l.stats.Add("wait_ms_total", waited.Milliseconds())
+	}
+
+	start := time.Now()
+	var once atomic.Bool
+	return func() {
+		if !once.CompareAndSwap(false, true) {
+			return
+		}
+		l.observe(time.Since(start))
+		l.stats.Add("in_flight", -1)
+		<-l.slots
+	}
+}
+
+// observe folds a holding time into the moving average (alpha = 1/5).
+func (l *Limiter) observe(d time.Duration) {
+	for {
+		old := l.avgService.Load()
+		next := int64(d)
+		if old > 0 {
+			next = old + (int64(d)-old)/5
+		}
+		if l.avgService.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// estimatedWait is how long the waiter at queue position pos should expect
+// to wait for a slot, 0 while no service time has been observed yet.
+func (l *Limiter) estimatedWait(pos int64) time.Duration {
+	avg := l.avgService.Load()
+	if avg == 0 {
+		return 0
+	}
+	return time.Duration(avg * pos / int64(cap(l.slots)))
+}
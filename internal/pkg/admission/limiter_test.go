@@ -0,0 +1,203 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+)
+
+var limiters atomic.Int64
+
+// newTestLimiter gives every limiter its own expvar name; New panics on reuse.
+func newTestLimiter(maxInFlight, maxQueue int, maxWait time.Duration) *Limiter {
+	return New(fmt.Sprintf("admission_test_%d", limiters.Add(1)), maxInFlight, maxQueue, maxWait)
+}
+
+func acquire(t *testing.T, l *Limiter) func() {
+	t.Helper()
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	return release
+}
+
+func waitQueued(t *testing.T, l *Limiter, n int64) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if l.queued.Load() == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("queue never reached %d", n)
+}
+
+func rejection(t *testing.T, err error) RejectedError {
+	t.Helper()
+	var rej RejectedError
+	if !errors.As(err, &rej) || !errors.Is(err, domain.ErrOverloaded) {
+		t.Fatalf("err = %v, want a RejectedError", err)
+	}
+	return rej
+}
+
+func TestAcquireQueueFull(t *testing.T) {
+	l := newTestLimiter(1, 1, time.Minute)
+	release := acquire(t, l)
+
+	waiter := make(chan error, 1)
+	go func() {
+		r, err := l.Acquire(context.Background())
+		if err == nil {
+			r()
+		}
+		waiter <- err
+	}()
+	waitQueued(t, l, 1)
+
+	_, err := l.Acquire(context.Background())
+	if rej := rejection(t, err); rej.Reason != "queue full" {
+		t.Errorf("reason = %q, want queue full", rej.Reason)
+	}
+
+	release()
+	if err := <-waiter; err != nil {
+		t.Errorf("queued waiter: %v, want it admitted", err)
+	}
+}
+
+func TestAcquireShedsShortDeadline(t *testing.T) {
+	l := newTestLimiter(1, 10, time.Minute)
+	l.avgService.Store(int64(100 * time.Millisecond))
+	defer acquire(t, l)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := l.Acquire(ctx)
+	rej := rejection(t, err)
+	if waited := time.Since(start); waited > 25*time.Millisecond {
+		t.Errorf("rejected after %v, want up front", waited)
+	}
+	if !strings.Contains(rej.Reason, "deadline too short") {
+		t.Errorf("reason = %q, want deadline too short", rej.Reason)
+	}
+	if rej.RetryAfter != 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want the 100ms estimate", rej.RetryAfter)
+	}
+}
+
+func TestAcquireAdmitsLongDeadline(t *testing.T) {
+	l := newTestLimiter(1, 10, time.Minute)
+	l.avgService.Store(int64(10 * time.Millisecond))
+	release := acquire(t, l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		r, err := l.Acquire(ctx)
+		if err == nil {
+			r()
+		}
+		done <- err
+	}()
+	waitQueued(t, l, 1)
+	release()
+	if err := <-done; err != nil {
+		t.Errorf("Acquire: %v, want it admitted", err)
+	}
+}
+
+func TestAcquireDeadlineWhileQueued(t *testing.T) {
+	l := newTestLimiter(1, 10, time.Minute)
+	defer acquire(t, l)()
+
+	// no service time observed yet, so it is queued and times out there
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := l.Acquire(ctx)
+	if rej := rejection(t, err); rej.Reason != "deadline reached while queued" {
+		t.Errorf("reason = %q, want deadline reached while queued", rej.Reason)
+	}
+}
+
+func TestAcquireMaxWait(t *testing.T) {
+	l := newTestLimiter(1, 10, 20*time.Millisecond)
+	defer acquire(t, l)()
+
+	_, err := l.Acquire(context.Background())
+	if rej := rejection(t, err); !strings.HasPrefix(rej.Reason, "queue wait exceeded") {
+		t.Errorf("reason = %q, want queue wait exceeded", rej.Reason)
+	}
+}
+
+func TestAcquireCancelled(t *testing.T) {
+	l := newTestLimiter(1, 10, time.Minute)
+	defer acquire(t, l)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitQueued(t, l, 1)
+		cancel()
+	}()
+	_, err := l.Acquire(ctx)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, domain.ErrOverloaded) {
+		t.Errorf("err = %v, want context.Canceled and no rejection", err)
+	}
+}
+
+func TestReleaseIsIdempotent(t *testing.T) {
+	l := newTestLimiter(2, 10, 20*time.Millisecond)
+	release := acquire(t, l)
+	other := acquire(t, l)
+	release()
+	release() // must not free other's slot
+
+	third := acquire(t, l)
+	if _, err := l.Acquire(context.Background()); err == nil {
+		t.Error("a third holder got in next to two, want it queued and timed out")
+	}
+	third()
+	other()
+}
+
+func TestEstimatedWait(t *testing.T) {
+	tests := []struct {
+		slots int
+		avg   time.Duration
+		pos   int64
+		want  time.Duration
+	}{
+		{1, 0, 3, 0},
+		{1, 100 * time.Millisecond, 1, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 1, 50 * time.Millisecond},
+		{2, 100 * time.Millisecond, 4, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		l := newTestLimiter(tt.slots, 10, time.Minute)
+		l.avgService.Store(int64(tt.avg))
+		if got := l.estimatedWait(tt.pos); got != tt.want {
+			t.Errorf("estimatedWait(%d) with %d slots, avg %v = %v, want %v", tt.pos, tt.slots, tt.avg, got, tt.want)
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	l := newTestLimiter(1, 1, time.Minute)
+	l.observe(100 * time.Millisecond)
+	if got := time.Duration(l.avgService.Load()); got != 100*time.Millisecond {
+		t.Errorf("first observation: avg = %v, want 100ms", got)
+	}
+	l.observe(200 * time.Millisecond)
+	if got := time.Duration(l.avgService.Load()); got != 120*time.Millisecond {
+		t.Errorf("second observation: avg = %v, want 120ms", got)
+	}
+}
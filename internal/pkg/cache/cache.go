@@ -0,0 +1,157 @@
+// Package cache provides the byte stores behind the OCR and transaction
+// caches: an in-memory LRU with TTL and an optional on-disk store that
+// survives restarts.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte)
+}
+
+// expiringStore is a Store that can hand an entry over with its expiry, so
+// a copy in another store does not outlive it.
+type expiringStore interface {
+	GetEntry(key string) (val []byte, expires time.Time, ok bool)
+	SetUntil(key string, val []byte, expires time.Time)
+}
+
+// Key hashes its parts into a fixed-size key. Parts are separated so that
+// ("ab", "c") and ("a", "bc") do not collide.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashBytes is the SHA-256 of raw content such as uploaded image bytes.
+func HashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ===== in-memory LRU =====
+
+type entry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func NewLRU(capacity int, ttl time.Duration) *LRU {
+	return &LRU{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(key string) ([]byte, bool) {
+	val, _, ok := c.GetEntry(key)
+	return val, ok
+}
+
+// GetEntry is Get that also returns when the entry expires.
+func (c *LRU) GetEntry(key string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	e := el.Value.(*entry)
+	if time.Now().After(e.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, time.Time{}, false
+	}
+	c.ll.MoveToFront(el)
+	return e.val, e.expires, true
+}
+
+func (c *LRU) Set(key string, val []byte) {
+	c.SetUntil(key, val, time.Now().Add(c.ttl))
+}
+
+// SetUntil stores val to expire at expires instead of after the TTL.
+func (c *LRU) SetUntil(key string, val []byte, expires time.Time) {
+	if !time.Now().Before(expires) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.val, e.expires = val, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry{key: key, val: val, expires: expires})
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+}
+
+// ===== tiers =====
+
+// Tiered reads through stores in order (fastest first) and back-fills the
+// faster tiers on a hit in a slower one, keeping the entry's expiry when
+// both tiers can carry it. Writes go to every tier.
+type Tiered []Store
+
+func (t Tiered) Get(key string) ([]byte, bool) {
+	for i, s := range t {
+		val, expires, ok := getEntry(s, key)
+		if !ok {
+			continue
+		}
+		for _, faster := range t[:i] {
+			if es, isExpiring := faster.(expiringStore); isExpiring && !expires.IsZero() {
+				es.SetUntil(key, val, expires)
+			} else {
+				faster.Set(key, val)
+			}
+		}
+		return val, true
+	}
+	return nil, false
+}
+
+// getEntry reads key from s; expires is zero when s cannot tell.
+func getEntry(s Store, key string) ([]byte, time.Time, bool) {
+	if es, ok := s.(expiringStore); ok {
+		return es.GetEntry(key)
+	}
+	val, ok := s.Get(key)
+	return val, time.Time{}, ok
+}
+
+func (t Tiered) Set(key string, val []byte) {
+	for _, s := range t {
+		s.Set(key, val)
+	}
+}
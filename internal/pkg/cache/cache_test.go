@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU(2, time.Hour)
+	c.Set("a", []byte("1"))
+	c.Set("b", []byte("2"))
+	c.Get("a") // b is now the least recently used
+	c.Set("c", []byte("3"))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b survived, want it evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s evicted, want it kept", k)
+		}
+	}
+
+	// overwriting an entry does not grow the cache
+	c.Set("a", []byte("1b"))
+	if v, _ := c.Get("a"); string(v) != "1b" {
+		t.Errorf("a = %q, want 1b", v)
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c evicted by an overwrite")
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c := NewLRU(10, 20*time.Millisecond)
+	c.Set("a", []byte("1"))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing before its TTL")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a still there after its TTL")
+	}
+}
+
+func TestLRUSetUntil(t *testing.T) {
+	c := NewLRU(10, time.Hour)
+	expires := time.Now().Add(20 * time.Millisecond)
+	c.SetUntil("a", []byte("1"), expires)
+	if _, got, ok := c.GetEntry("a"); !ok || !got.Equal(expires) {
+		t.Fatalf("GetEntry = %v, %v, want %v", got, ok, expires)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a outlived the expiry it was set with")
+	}
+
+	c.SetUntil("b", []byte("2"), time.Now().Add(-time.Second))
+	if _, ok := c.Get("b"); ok {
+		t.Error("an already expired entry was stored")
+	}
+}
+
+func TestTieredBackfill(t *testing.T) {
+	mem, disk := NewLRU(10, time.Hour), newTestDisk(t, time.Hour)
+	tiers := Tiered{mem, disk}
+
+	// written long ago: 10ms of its hour left
+	expires := time.Now().Add(10 * time.Millisecond)
+	disk.SetUntil("k", []byte("v"), expires)
+
+	if v, ok := tiers.Get("k"); !ok || string(v) != "v" {
+		t.Fatalf("Get = %q, %v, want v", v, ok)
+	}
+	_, got, ok := mem.GetEntry("k")
+	if !ok {
+		t.Fatal("hit on disk not copied into memory")
+	}
+	if got.Sub(expires).Abs() > time.Second {
+		t.Errorf("memory copy expires at %v, want the disk entry's %v", got, expires)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := tiers.Get("k"); ok {
+		t.Error("entry outlived its expiry through the memory copy")
+	}
+}
+
+func TestTieredSet(t *testing.T) {
+	a, b := NewLRU(10, time.Hour), NewLRU(10, time.Hour)
+	Tiered{a, b}.Set("k", []byte("v"))
+	for i, s := range []Store{a, b} {
+		if _, ok := s.Get("k"); !ok {
+			t.Errorf("tier %d missing the entry", i)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error(`Key("ab", "c") == Key("a", "bc")`)
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key is not deterministic")
+	}
+}
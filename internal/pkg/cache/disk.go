@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Disk stores one file per key under dir; expiry is based on the file's
+// modification time. Each file starts with the SHA-256 of the value, so a
+// file that was truncated or damaged reads as a miss. Keys must be safe
+// file names (see Key).
+type Disk struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDisk creates dir if needed and starts a janitor removing expired files
+// until ctx is done.
+func NewDisk(ctx context.Context, dir string, ttl time.Duration) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	d := &Disk{dir: dir, ttl: ttl}
+	go d.janitor(ctx, time.Hour)
+	return d, nil
+}
+
+func (d *Disk) path(key string) string {
+	return filepath.Join(d.dir, key)
+}
+
+func (d *Disk) Get(key string) ([]byte, bool) {
+	val, _, ok := d.GetEntry(key)
+	return val, ok
+}
+
+// GetEntry is Get that also returns when the entry expires.
+func (d *Disk) GetEntry(key string) ([]byte, time.Time, bool) {
+	p := d.path(key)
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	expires := fi.ModTime().Add(d.ttl)
+	if time.Now().After(expires) {
+		_ = os.Remove(p)
+		return nil, time.Time{}, false
+	}
+	raw, err := os.ReadFile(p)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	val, ok := checked(raw)
+	if !ok {
+		log.Printf("cache: dropping corrupt disk entry %s", key)
+		_ = os.Remove(p)
+		return nil, time.Time{}, false
+	}
+	return val, expires, true
+}
+
+// checked splits a file into its checksum and value and verifies them.
+func checked(raw []byte) ([]byte, bool) {
+	if len(raw) < sha256.Size {
+		return nil, false
+	}
+	val := raw[sha256.Size:]
+	sum := sha256.Sum256(val)
+	return val, bytes.Equal(raw[:sha256.Size], sum[:])
+}
+
+func (d *Disk) Set(key string, val []byte) {
+	d.SetUntil(key, val, time.Now().Add(d.ttl))
+}
+
+// SetUntil stores val to expire at expires instead of after the TTL. It
+// writes through a temp file and rename so readers never see a partial
+// value, and dates the file back so its age says when it expires.
+func (d *Disk) SetUntil(key string, val []byte, expires time.Time) {
+	if !time.Now().Before(expires) {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		log.Printf("cache: disk write failed: %v", err)
+		return
+	}
+	sum := sha256.Sum256(val)
+	_, werr := tmp.Write(append(sum[:], val...))
+	cerr := tmp.Close()
+	if werr == nil && cerr == nil {
+		mtime := expires.Add(-d.ttl)
+		cerr = os.Chtimes(tmp.Name(), mtime, mtime)
+	}
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		log.Printf("cache: disk write failed: %v %v", werr, cerr)
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+		log.Printf("cache: disk write failed: %v", err)
+	}
+}
+
+func (d *Disk) janitor(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		d.sweep()
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (d *Disk) sweep() {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		if time.Since(fi.ModTime()) > d.ttl {
+			_ = os.Remove(filepath.Join(d.dir, e.Name()))
+		}
+	}
+}
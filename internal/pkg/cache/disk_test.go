@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDisk(t *testing.T, ttl time.Duration) *Disk {
+	t.Helper()
+	d, err := NewDisk(t.Context(), t.TempDir(), ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDiskRoundTrip(t *testing.T) {
+	d := newTestDisk(t, time.Hour)
+	key := Key("ocr", "x")
+	d.Set(key, []byte("hello"))
+	if v, ok := d.Get(key); !ok || string(v) != "hello" {
+		t.Errorf("Get = %q, %v, want hello", v, ok)
+	}
+	if _, ok := d.Get(Key("other")); ok {
+		t.Error("hit for a key never set")
+	}
+}
+
+func TestDiskCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(raw []byte) []byte
+	}{
+		{"truncated value", func(raw []byte) []byte { return raw[:len(raw)-2] }},
+		{"flipped byte", func(raw []byte) []byte { raw[len(raw)-1] ^= 0xff; return raw }},
+		{"shorter than the checksum", func(raw []byte) []byte { return raw[:5] }},
+		{"empty", func([]byte) []byte { return nil }},
+	}
+	for _, tt := range tests {
+		d := newTestDisk(t, time.Hour)
+		key := Key(tt.name)
+		d.Set(key, []byte("some OCR text"))
+		p := filepath.Join(d.dir, key)
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, tt.corrupt(raw), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if v, ok := d.Get(key); ok {
+			t.Errorf("%s: Get = %q, want a miss", tt.name, v)
+		}
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s: corrupt file kept", tt.name)
+		}
+	}
+}
+
+func TestDiskExpiry(t *testing.T) {
+	d := newTestDisk(t, time.Hour)
+	key := Key("old")
+	d.Set(key, []byte("v"))
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(d.dir, key), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.Get(key); ok {
+		t.Error("hit for an entry past its TTL")
+	}
+}
+
+func TestDiskSetUntil(t *testing.T) {
+	d := newTestDisk(t, time.Hour)
+	key := Key("k")
+	expires := time.Now().Add(10 * time.Minute)
+	d.SetUntil(key, []byte("v"), expires)
+	_, got, ok := d.GetEntry(key)
+	if !ok {
+		t.Fatal("entry missing")
+	}
+	if got.Sub(expires).Abs() > time.Second {
+		t.Errorf("expires at %v, want %v", got, expires)
+	}
+}
+
+func TestDiskSweep(t *testing.T) {
+	d := newTestDisk(t, time.Hour)
+	fresh, stale := Key("fresh"), Key("stale")
+	d.Set(fresh, []byte("v"))
+	d.Set(stale, []byte("v"))
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(d.dir, stale), old, old); err != nil {
+		t.Fatal(err)
+	}
+	d.sweep()
+	if _, err := os.Stat(filepath.Join(d.dir, stale)); !os.IsNotExist(err) {
+		t.Error("expired file not swept")
+	}
+	if _, err := os.Stat(filepath.Join(d.dir, fresh)); err != nil {
+		t.Errorf("fresh file swept: %v", err)
+	}
+}
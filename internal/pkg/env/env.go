@@ -0,0 +1,81 @@
+// Package env reads typed configuration from environment variables, falling
+// back to a default when a variable is unset or malformed.
+package env
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func String(k, def string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return def
+}
+
+func Int(k string, def int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %d", k, v, def)
+		return def
+	}
+	return n
+}
+
+func Bool(k string, def bool) bool {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %v", k, v, def)
+		return def
+	}
+	return b
+}
+
+func Float(k string, def float64) float64 {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %v", k, v, def)
+		return def
+	}
+	return f
+}
+
+func Duration(k string, def time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %v", k, v, def)
+		return def
+	}
+	return d
+}
+
+// List splits a comma separated variable, dropping empty entries.
+func List(k string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(k), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
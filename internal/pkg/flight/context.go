@@ -0,0 +1,121 @@
+package flight
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// workContext is the context shared work runs on. Unlike the contexts from
+// context.WithDeadline, its deadline can move out when a caller with more
+// time joins, so deadline-aware code (admission) sees how long the work may
+// still take. Contexts derived from it keep the deadline they started with.
+type workContext struct {
+	values context.Context // the first caller's, without its cancellation
+	done   chan struct{}
+
+	mu       sync.Mutex
+	err      error
+	deadline time.Time // zero when a waiter has no deadline
+	timer    *time.Timer
+	stage    string // set by SetStage
+}
+
+// workKey finds the workContext among the values of contexts derived from
+// it.
+type workKey struct{}
+
+// SetStage records how far the work running on ctx has got. A caller that
+// stops waiting gets it in its AbandonedError. Outside Group.Do it does
+// nothing.
+func SetStage(ctx context.Context, stage string) {
+	if c, ok := ctx.Value(workKey{}).(*workContext); ok {
+		c.mu.Lock()
+		c.stage = stage
+		c.mu.Unlock()
+	}
+}
+
+func (c *workContext) currentStage() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stage
+}
+
+func newWorkContext(ctx context.Context) *workContext {
+	c := &workContext{values: context.WithoutCancel(ctx), done: make(chan struct{})}
+	if d, ok := ctx.Deadline(); ok {
+		// an expired deadline fires the timer at once, before c.timer is set
+		c.mu.Lock()
+		c.deadline = d
+		c.timer = time.AfterFunc(time.Until(d), c.expire)
+		c.mu.Unlock()
+	}
+	return c
+}
+
+// extend makes room for a caller joining with ctx.
+func (c *workContext) extend(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil || c.deadline.IsZero() {
+		return
+	}
+	d, ok := ctx.Deadline()
+	switch {
+	case !ok:
+		c.deadline = time.Time{}
+		c.timer.Stop()
+	case d.After(c.deadline):
+		c.deadline = d
+		c.timer.Reset(time.Until(d))
+	}
+}
+
+// expire runs when the timer fires; a caller may have just extended the
+// deadline.
+func (c *workContext) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.deadline.IsZero() && !time.Now().Before(c.deadline) {
+		c.cancelLocked(context.DeadlineExceeded)
+	}
+}
+
+func (c *workContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelLocked(err)
+}
+
+func (c *workContext) cancelLocked(err error) {
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	close(c.done)
+}
+
+func (c *workContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deadline, !c.deadline.IsZero()
+}
+
+func (c *workContext) Done() <-chan struct{} { return c.done }
+
+func (c *workContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *workContext) Value(key any) any {
+	if key == (workKey{}) {
+		return c
+	}
+	return c.values.Value(key)
+}
@@ -0,0 +1,79 @@
+package flight
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWorkContextExpires(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	c := newWorkContext(ctx)
+
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("work context never expired")
+	}
+	if !errors.Is(c.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", c.Err())
+	}
+}
+
+func TestWorkContextExtend(t *testing.T) {
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+
+	c := newWorkContext(short)
+	c.extend(long)
+	want, _ := long.Deadline()
+	if d, ok := c.Deadline(); !ok || !d.Equal(want) {
+		t.Errorf("Deadline() = %v, %v, want %v", d, ok, want)
+	}
+
+	// a shorter deadline joining later does not pull it back in
+	c.extend(short)
+	if d, _ := c.Deadline(); !d.Equal(want) {
+		t.Errorf("Deadline() after a shorter caller = %v, want %v", d, want)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if err := c.Err(); err != nil {
+		t.Errorf("work context expired at the first deadline: %v", err)
+	}
+	c.cancel(context.Canceled)
+}
+
+func TestWorkContextExtendWithoutDeadline(t *testing.T) {
+	short, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	c := newWorkContext(short)
+	c.extend(context.Background())
+	if d, ok := c.Deadline(); ok {
+		t.Errorf("Deadline() = %v, want none", d)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := c.Err(); err != nil {
+		t.Errorf("work context expired: %v", err)
+	}
+	c.cancel(context.Canceled)
+}
+
+func TestWorkContextKeepsValues(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "v"))
+	c := newWorkContext(parent)
+	cancel()
+
+	if got := c.Value(key{}); got != "v" {
+		t.Errorf("Value() = %v, want v", got)
+	}
+	if err := c.Err(); err != nil {
+		t.Errorf("work context cancelled with its parent: %v", err)
+	}
+}
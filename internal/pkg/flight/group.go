@@ -0,0 +1,92 @@
+// Package flight coalesces identical concurrent calls into one execution,
+// like x/sync/singleflight, but with cancellation that is safe to share: the
+// work is only cancelled once every caller waiting for it has gone.
+package flight
+
+import (
+	"context"
+	"sync"
+)
+
+type call[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+	ctx     *workContext
+}
+
+// Group is safe for concurrent use; the zero value is ready to use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// AbandonedError is what a caller that stopped waiting before the work
+// finished gets: its ctx.Err() and the stage the work was in.
+type AbandonedError struct {
+	Stage string // the last one passed to SetStage, "" if none was
+	Err   error
+}
+
+func (e *AbandonedError) Error() string { return e.Err.Error() }
+func (e *AbandonedError) Unwrap() error { return e.Err }
+
+// Do runs fn once for all concurrent callers using the same key and hands
+// each of them the result. fn runs on a context that keeps the first
+// caller's values (metadata, loggers) but none of its cancellation; its
+// deadline is the latest of the waiting callers' deadlines (none if one of
+// them has none), moved out as callers join, and it is cancelled when the
+// last waiting caller's ctx is done. A caller whose ctx ends early gets an
+// AbandonedError wrapping ctx.Err() while the others keep waiting. shared
+// reports whether this caller joined work started by another.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (val T, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	c, shared := g.calls[key]
+	if shared {
+		c.waiters++
+		c.ctx.extend(ctx)
+	} else {
+		c = &call[T]{done: make(chan struct{}), waiters: 1, ctx: newWorkContext(ctx)}
+		g.calls[key] = c
+		go g.run(c, key, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			// nobody is left to use the result
+			c.ctx.cancel(context.Canceled)
+			g.forget(key, c)
+		}
+		g.mu.Unlock()
+		var zero T
+		return zero, shared, &AbandonedError{Stage: c.ctx.currentStage(), Err: ctx.Err()}
+	}
+}
+
+func (g *Group[T]) run(c *call[T], key string, fn func(ctx context.Context) (T, error)) {
+	defer c.ctx.cancel(context.Canceled)
+	c.val, c.err = fn(c.ctx)
+
+	g.mu.Lock()
+	g.forget(key, c)
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// forget removes c unless the key has already been taken by a newer call.
+// g.mu must be held.
+func (g *Group[T]) forget(key string, c *call[T]) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,203 @@
+package flight
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// result is what one Do call returned.
+type result struct {
+	val    string
+	shared bool
+	err    error
+}
+
+func doAsync(g *Group[string], ctx context.Context, key string, fn func(context.Context) (string, error)) <-chan result {
+	out := make(chan result, 1)
+	go func() {
+		v, shared, err := g.Do(ctx, key, fn)
+		out <- result{v, shared, err}
+	}()
+	return out
+}
+
+// blockingWork returns work that signals its context on started and waits
+// for release.
+func blockingWork(runs *atomic.Int32, started chan<- context.Context, release <-chan struct{}) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		runs.Add(1)
+		started <- ctx
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+func waitJoined(t *testing.T, g *Group[string], key string, waiters int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		g.mu.Lock()
+		c := g.calls[key]
+		n := 0
+		if c != nil {
+			n = c.waiters
+		}
+		g.mu.Unlock()
+		if n == waiters {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%d callers never joined %q", waiters, key)
+}
+
+func TestDoCoalesces(t *testing.T) {
+	var (
+		g       Group[string]
+		runs    atomic.Int32
+		started = make(chan context.Context, 2)
+		release = make(chan struct{})
+	)
+	fn := blockingWork(&runs, started, release)
+	first := doAsync(&g, context.Background(), "k", fn)
+	<-started
+	second := doAsync(&g, context.Background(), "k", fn)
+	waitJoined(t, &g, "k", 2)
+	close(release)
+
+	r1, r2 := <-first, <-second
+	if r1.val != "done" || r2.val != "done" || r1.err != nil || r2.err != nil {
+		t.Fatalf("results = %+v, %+v, want done twice", r1, r2)
+	}
+	if r1.shared || !r2.shared {
+		t.Errorf("shared = %v, %v, want false, true", r1.shared, r2.shared)
+	}
+	if n := runs.Load(); n != 1 {
+		t.Errorf("fn ran %d times, want 1", n)
+	}
+}
+
+func TestDoLeaderCancelled(t *testing.T) {
+	var (
+		g       Group[string]
+		runs    atomic.Int32
+		started = make(chan context.Context, 1)
+		release = make(chan struct{})
+	)
+	fn := blockingWork(&runs, started, release)
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := doAsync(&g, leaderCtx, "k", fn)
+	workCtx := <-started
+	follower := doAsync(&g, context.Background(), "k", fn)
+	waitJoined(t, &g, "k", 2)
+
+	cancelLeader()
+	r := <-leader
+	if !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("leader err = %v, want context.Canceled", r.err)
+	}
+	if err := workCtx.Err(); err != nil {
+		t.Fatalf("work cancelled with the leader: %v", err)
+	}
+
+	close(release)
+	if r := <-follower; r.val != "done" || r.err != nil {
+		t.Errorf("follower = %+v, want done", r)
+	}
+}
+
+func TestDoFollowerDetach(t *testing.T) {
+	var (
+		g       Group[string]
+		runs    atomic.Int32
+		started = make(chan context.Context, 1)
+		release = make(chan struct{})
+	)
+	fn := blockingWork(&runs, started, release)
+	leader := doAsync(&g, context.Background(), "k", fn)
+	workCtx := <-started
+	followerCtx, cancelFollower := context.WithCancel(context.Background())
+	follower := doAsync(&g, followerCtx, "k", fn)
+	waitJoined(t, &g, "k", 2)
+
+	cancelFollower()
+	if r := <-follower; !errors.Is(r.err, context.Canceled) || !r.shared {
+		t.Fatalf("follower = %+v, want a shared context.Canceled", r)
+	}
+	if err := workCtx.Err(); err != nil {
+		t.Fatalf("work cancelled with the follower: %v", err)
+	}
+
+	close(release)
+	if r := <-leader; r.val != "done" || r.err != nil {
+		t.Errorf("leader = %+v, want done", r)
+	}
+}
+
+func TestDoLastCallerCancelsWork(t *testing.T) {
+	var (
+		g       Group[string]
+		runs    atomic.Int32
+		started = make(chan context.Context, 2)
+	)
+	fn := blockingWork(&runs, started, make(chan struct{}))
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	first := doAsync(&g, ctx1, "k", fn)
+	workCtx := <-started
+	second := doAsync(&g, ctx2, "k", fn)
+	waitJoined(t, &g, "k", 2)
+
+	cancel1()
+	<-first
+	cancel2()
+	<-second
+	select {
+	case <-workCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("work not cancelled after every caller left")
+	}
+
+	// the key is free again: a new caller starts new work
+	third := doAsync(&g, context.Background(), "k", func(context.Context) (string, error) { return "again", nil })
+	if r := <-third; r.val != "again" || r.shared {
+		t.Errorf("third = %+v, want a fresh run", r)
+	}
+}
+
+func TestDoAbandonedStage(t *testing.T) {
+	var g Group[string]
+	staged := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	out := doAsync(&g, ctx, "k", func(ctx context.Context) (string, error) {
+		SetStage(ctx, "llm")
+		close(staged)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	<-staged
+	cancel()
+
+	r := <-out
+	var abandoned *AbandonedError
+	if !errors.As(r.err, &abandoned) {
+		t.Fatalf("err = %v, want an AbandonedError", r.err)
+	}
+	if abandoned.Stage != "llm" {
+		t.Errorf("Stage = %q, want llm", abandoned.Stage)
+	}
+	if !errors.Is(r.err, context.Canceled) {
+		t.Errorf("err = %v, want it to wrap context.Canceled", r.err)
+	}
+}
+
+func TestSetStageOutsideDo(t *testing.T) {
+	// must not panic or leak into anything
+	SetStage(context.Background(), "llm")
+}
@@ -12,15 +12,17 @@ type Server struct {
 	addr   string
 	lis    net.Listener
 	Server *grpc.Server
+	Health *health.Server
 }
 
-func New(addr string) *Server {
-	s := grpc.NewServer()
+func New(addr string, opts ...grpc.ServerOption) *Server {
+	s := grpc.NewServer(opts...)
 	hs := health.NewServer()
 	healthpb.RegisterHealthServer(s, hs)
 	return &Server{
 		addr:   addr,
 		Server: s, // add creds/interceptors here later
+		Health: hs,
 	}
 }
 
@@ -33,7 +35,18 @@ func (s *Server) Start() error {
 	return s.Server.Serve(lis)
 }
 
+// SetServing updates the grpc_health_v1 status of a service ("" is the
+// overall server status checked by grpc_health_probe).
+func (s *Server) SetServing(service string, serving bool) {
+	st := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		st = healthpb.HealthCheckResponse_SERVING
+	}
+	s.Health.SetServingStatus(service, st)
+}
+
 func (s *Server) Stop() {
+	s.Health.Shutdown()
 	s.Server.GracefulStop()
 	if s.lis != nil {
 		_ = s.lis.Close()
@@ -0,0 +1,158 @@
+package healthcheck
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/ports"
+)
+
+// Overall is the name under which the aggregate status is reported.
+const Overall = ""
+
+type result struct {
+	healthy   bool
+	msg       string
+	checkedAt time.Time
+}
+
+// Monitor runs dependency health checks in the background and caches the
+// results. It implements ports.HealthPort so RPC handlers can answer from
+// the cache instead of hitting dependencies on every probe.
+type Monitor struct {
+	checks   map[string]ports.HealthPort
+	names    []string
+	interval time.Duration
+	timeout  time.Duration
+	onChange func(name string, healthy bool)
+
+	mu      sync.RWMutex
+	results map[string]result
+}
+
+func NewMonitor(checks map[string]ports.HealthPort, interval, timeout time.Duration) *Monitor {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &Monitor{
+		checks:   checks,
+		names:    names,
+		interval: interval,
+		timeout:  timeout,
+		results:  make(map[string]result, len(checks)),
+	}
+}
+
+// OnChange registers a callback fired whenever a dependency, or the overall
+// status (name == Overall), flips between healthy and unhealthy. It is also
+// fired once per name after the first round of checks.
+func (m *Monitor) OnChange(fn func(name string, healthy bool)) {
+	m.onChange = fn
+}
+
+// Start runs one round of checks synchronously, so the service does not
+// report SERVING before anything was verified, then keeps checking every
+// interval until ctx is cancelled.
+func (m *Monitor) Start(ctx context.Context) {
+	m.runChecks(ctx)
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.runChecks(ctx)
+			}
+		}
+	}()
+}
+
+// Check implements ports.HealthPort from cached results.
+func (m *Monitor) Check(_ context.Context, name string) (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if name == Overall {
+		return m.overallLocked()
+	}
+	r, ok := m.results[name]
+	if !ok {
+		if _, known := m.checks[name]; known {
+			return false, name + ": not checked yet"
+		}
+		return false, "unknown dependency: " + name
+	}
+	return r.healthy, name + ": " + r.msg
+}
+
+func (m *Monitor) overallLocked() (bool, string) {
+	var failing []string
+	for _, name := range m.names {
+		r, ok := m.results[name]
+		if !ok {
+			failing = append(failing, name+": not checked yet")
+		} else if !r.healthy {
+			failing = append(failing, name+": "+r.msg)
+		}
+	}
+	if len(failing) > 0 {
+		return false, "unhealthy: " + strings.Join(failing, "; ")
+	}
+	return true, "OK"
+}
+
+func (m *Monitor) runChecks(ctx context.Context) {
+	var wg sync.WaitGroup
+	fresh := make([]result, len(m.names))
+	for i, name := range m.names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, m.timeout)
+			defer cancel()
+			healthy, msg := m.checks[name].Check(cctx, name)
+			fresh[i] = result{healthy: healthy, msg: msg, checkedAt: time.Now()}
+		}(i, name)
+	}
+	wg.Wait()
+
+	type change struct {
+		name    string
+		healthy bool
+	}
+	var changes []change
+
+	m.mu.Lock()
+	wasHealthy, _ := m.overallLocked()
+	first := len(m.results) == 0
+	for i, name := range m.names {
+		prev, seen := m.results[name]
+		if !seen || prev.healthy != fresh[i].healthy {
+			changes = append(changes, change{name, fresh[i].healthy})
+			log.Printf("health: %s healthy=%v (%s)", name, fresh[i].healthy, fresh[i].msg)
+		}
+		m.results[name] = fresh[i]
+	}
+	isHealthy, _ := m.overallLocked()
+	m.mu.Unlock()
+
+	if first || wasHealthy != isHealthy {
+		changes = append(changes, change{Overall, isHealthy})
+	}
+	if m.onChange == nil {
+		return
+	}
+	for _, c := range changes {
+		m.onChange(c.name, c.healthy)
+	}
+}
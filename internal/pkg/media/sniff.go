@@ -0,0 +1,99 @@
+// Package media identifies uploads by their content rather than by what the
+// client claims they are, and reads image dimensions without decoding pixels.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/jpeg" // register for DecodeConfig
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Format is a supported upload type.
+type Format struct {
+	Name     string
+	MIME     string
+	Filename string // sent to upstream APIs that infer the type from it
+}
+
+var (
+	JPEG = Format{"jpeg", "image/jpeg", "image.jpg"}
+	PNG  = Format{"png", "image/png", "image.png"}
+	WebP = Format{"webp", "image/webp", "image.webp"}
+	HEIC = Format{"heic", "image/heic", "image.heic"}
+	PDF  = Format{"pdf", "application/pdf", "document.pdf"}
+)
+
+// ErrNoDimensions is returned when an image header does not state its size.
+var ErrNoDimensions = errors.New("image dimensions not found")
+
+// heifBrands are the ISO-BMFF major brands of HEIC/HEIF still images.
+var heifBrands = map[string]bool{
+	"heic": true, "heix": true, "hevc": true, "hevx": true,
+	"heim": true, "heis": true, "mif1": true, "msf1": true,
+}
+
+// Sniff reports the format of data, or false when it is none we accept.
+func Sniff(data []byte) (Format, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xFF\xD8\xFF")):
+		return JPEG, true
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG, true
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return WebP, true
+	case len(data) >= 12 && string(data[4:8]) == "ftyp" && heifBrands[string(data[8:12])]:
+		return HEIC, true
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return PDF, true
+	}
+	return Format{}, false
+}
+
+// IsImage reports whether f is a raster image (as opposed to a PDF).
+func (f Format) IsImage() bool { return f != PDF && f != Format{} }
+
+// Dimensions reads the pixel size of an image from its header only, so a
+// decompression bomb can be refused before any pixel is decoded.
+func Dimensions(data []byte, f Format) (int, int, error) {
+	if f == HEIC {
+		return heicDimensions(data)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// heicDimensions returns the largest image spatial extent ('ispe') property
+// in the file. A grid image lists its tiles too; the full image is the
+// largest one.
+func heicDimensions(data []byte) (int, int, error) {
+	var w, h uint32
+	for i := 0; ; {
+		k := bytes.Index(data[i:], []byte("ispe"))
+		if k < 0 {
+			break
+		}
+		k += i
+		// box: size(4) "ispe" version/flags(4) width(4) height(4)
+		if k >= 4 && k+16 <= len(data) && binary.BigEndian.Uint32(data[k-4:]) == 20 {
+			bw := binary.BigEndian.Uint32(data[k+8:])
+			bh := binary.BigEndian.Uint32(data[k+12:])
+			// two uint32s always multiply within a uint64
+			if uint64(bw)*uint64(bh) > uint64(w)*uint64(h) {
+				w, h = bw, bh
+			}
+		}
+		i = k + 4
+	}
+	if w == 0 || h == 0 {
+		return 0, 0, ErrNoDimensions
+	}
+	return int(w), int(h), nil
+}
@@ -0,0 +1,99 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(w, h int) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)))
+	return buf.Bytes()
+}
+
+func encodeJPEG(w, h int) []byte {
+	var buf bytes.Buffer
+	_ = jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil)
+	return buf.Bytes()
+}
+
+// ispeBox is an image spatial extent property box.
+func ispeBox(w, h uint32) []byte {
+	box := make([]byte, 20)
+	binary.BigEndian.PutUint32(box, 20)
+	copy(box[4:], "ispe")
+	binary.BigEndian.PutUint32(box[12:], w)
+	binary.BigEndian.PutUint32(box[16:], h)
+	return box
+}
+
+// heic is a HEIC file header followed by the given boxes.
+func heic(boxes ...[]byte) []byte {
+	data := []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic")
+	for _, b := range boxes {
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+		ok   bool
+	}{
+		{"jpeg", encodeJPEG(2, 2), JPEG, true},
+		{"png", encodePNG(2, 2), PNG, true},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), WebP, true},
+		{"heic", heic(), HEIC, true},
+		{"heif mif1 brand", []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00"), HEIC, true},
+		{"pdf", []byte("%PDF-1.7\n"), PDF, true},
+		{"mp4 is not heic", []byte("\x00\x00\x00\x18ftypisom\x00\x00\x00\x00"), Format{}, false},
+		{"gif", []byte("GIF89a"), Format{}, false},
+		{"text", []byte("hello"), Format{}, false},
+		{"empty", nil, Format{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Sniff(tt.data)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: Sniff = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+	if PDF.IsImage() || (Format{}).IsImage() || !HEIC.IsImage() {
+		t.Error("IsImage: want true for raster formats only")
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		f            Format
+		wantW, wantH int
+		wantErr      bool
+	}{
+		{"png", encodePNG(640, 480), PNG, 640, 480, false},
+		{"jpeg", encodeJPEG(33, 17), JPEG, 33, 17, false},
+		{"truncated png", encodePNG(640, 480)[:12], PNG, 0, 0, true},
+		{"heic", heic(ispeBox(4032, 3024)), HEIC, 4032, 3024, false},
+		{"heic grid: largest extent", heic(ispeBox(512, 512), ispeBox(4032, 3024), ispeBox(512, 512)), HEIC, 4032, 3024, false},
+		{"heic area past int32", heic(ispeBox(512, 512), ispeBox(100000, 100000)), HEIC, 100000, 100000, false},
+		{"heic without ispe", heic(), HEIC, 0, 0, true},
+		{"heic box of the wrong size", heic([]byte("\x00\x00\x00\x10ispe\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x01\x00")), HEIC, 0, 0, true},
+	}
+	for _, tt := range tests {
+		w, h, err := Dimensions(tt.data, tt.f)
+		if (err != nil) != tt.wantErr || w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: Dimensions = %dx%d, %v; want %dx%d (error %v)", tt.name, w, h, err, tt.wantW, tt.wantH, tt.wantErr)
+		}
+	}
+	if _, _, err := Dimensions(heic(), HEIC); !errors.Is(err, ErrNoDimensions) {
+		t.Errorf("heic without ispe: err = %v, want ErrNoDimensions", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Package progress carries a per-request reporter in the context so the
+// pipeline and the adapters can announce what they are doing without
+// knowing who listens.
+package progress
+
+import (
+	"context"
+	"time"
+)
+
+type Stage string
+
+const (
+	Received      Stage = "received"
+	Preprocessed  Stage = "preprocessed"
+	OCRStarted    Stage = "ocr_started"
+	OCRRetrying   Stage = "ocr_retrying"
+	OCRFinished   Stage = "ocr_finished"
+	LLMGenerating Stage = "llm_generating"
+)
+
+type Event struct {
+	Stage   Stage
+	Message string
+	// OCRRetrying
+	Attempt int
+	RetryIn time.Duration
+	// OCRFinished
+	Text string
+	// OCRFinished of a PDF whose OCR failed on some pages
+	TotalPages  int
+	FailedPages []int
+}
+
+// Reporter receives the events of one request, in order.
+type Reporter func(Event)
+
+type ctxKey struct{}
+
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, ctxKey{}, r)
+}
+
+// Enabled reports whether somebody listens for events on ctx.
+func Enabled(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKey{}).(Reporter)
+	return ok
+}
+
+// Report sends ev to the reporter in ctx, if any.
+func Report(ctx context.Context, ev Event) {
+	if r, ok := ctx.Value(ctxKey{}).(Reporter); ok {
+		r(ev)
+	}
+}
@@ -0,0 +1,141 @@
+package thaidate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+	"อาทิตย์": time.Sunday, "จันทร์": time.Monday, "อังคาร": time.Tuesday, "พุธ": time.Wednesday,
+	"พฤหัส": time.Thursday, "พฤหัสบดี": time.Thursday, "ศุกร์": time.Friday, "เสาร์": time.Saturday,
+}
+
+const (
+	enWeekday = `monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+	// อาทิตย์ alone also means "week", so Sunday needs the วัน prefix
+	thWeekday = `จันทร์|อังคาร|พุธ|พฤหัสบดี|พฤหัส|ศุกร์|เสาร์`
+	thAgo     = `(?:ที่แล้ว|ที่ผ่านมา|ก่อน)`
+)
+
+// relativeRule turns a match into a day, given today at midnight.
+type relativeRule struct {
+	re  *regexp.Regexp
+	day func(g []string, today time.Time) (time.Time, bool)
+}
+
+func back(n int) func([]string, time.Time) (time.Time, bool) {
+	return func(_ []string, today time.Time) (time.Time, bool) { return today.AddDate(0, 0, -n), true }
+}
+
+// lastWeekday is the latest wd before today; bare names ("Friday") may
+// also mean today.
+func lastWeekday(includeToday bool) func([]string, time.Time) (time.Time, bool) {
+	return func(g []string, today time.Time) (time.Time, bool) {
+		wd, ok := weekdays[g[1]]
+		if !ok {
+			return time.Time{}, false
+		}
+		n := (int(today.Weekday()) - int(wd) + 7) % 7
+		if n == 0 && !includeToday {
+			n = 7
+		}
+		return today.AddDate(0, 0, -n), true
+	}
+}
+
+func daysAgo(g []string, today time.Time) (time.Time, bool) {
+	n, err := strconv.Atoi(g[1])
+	if err != nil || n > 366 {
+		return time.Time{}, false
+	}
+	return today.AddDate(0, 0, -n), true
+}
+
+// dayMonth is a day and month without a year: the latest such date that is
+// not after today.
+func dayMonth(dayGroup, monthGroup int) func([]string, time.Time) (time.Time, bool) {
+	return func(g []string, today time.Time) (time.Time, bool) {
+		m, ok := months[strings.ReplaceAll(g[monthGroup], ".", "")]
+		d, _ := strconv.Atoi(g[dayGroup])
+		if !ok || d < 1 || d > 31 {
+			return time.Time{}, false
+		}
+		for _, y := range []int{today.Year(), today.Year() - 1} {
+			t := time.Date(y, m, d, 0, 0, 0, 0, today.Location())
+			if t.Day() == d && !t.After(today) {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+}
+
+// relativeRules are tried on lower-cased lines. When several match, the
+// earliest match wins, and on a tie the rule listed first, so the longer
+// phrases come before the ones they contain.
+var relativeRules = []relativeRule{
+	{regexp.MustCompile(`\b(?:the\s+)?day\s+before\s+yesterday\b|เมื่อวานซืน`), back(2)},
+	{regexp.MustCompile(`\byesterday\b|\blast\s+night\b|เมื่อวาน|เมื่อคืน`), back(1)},
+	{regexp.MustCompile(`\btoday\b|\btonight\b|\bthis\s+(?:morning|afternoon|evening)\b|วันนี้|เมื่อเช้า|คืนนี้`), back(0)},
+	{regexp.MustCompile(`\b(\d{1,3})\s+days?\s+ago\b|(\d{1,3})\s*วัน` + thAgo), func(g []string, today time.Time) (time.Time, bool) {
+		if g[1] == "" {
+			g = g[1:]
+		}
+		return daysAgo(g, today)
+	}},
+	{regexp.MustCompile(`\blast\s+(` + enWeekday + `|sun|mon|tues?|wed|thu(?:rs?)?|fri|sat)\b`), lastWeekday(false)},
+	{regexp.MustCompile(`(?:วัน)?(` + thWeekday + `)\s*` + thAgo), lastWeekday(false)},
+	{regexp.MustCompile(`วัน(อาทิตย์)\s*` + thAgo), lastWeekday(false)},
+	{regexp.MustCompile(`\blast\s+week\b|(?:อาทิตย์|สัปดาห์)\s*` + thAgo), back(7)},
+	{regexp.MustCompile(`\b(` + enWeekday + `)\b`), lastWeekday(true)},
+	{regexp.MustCompile(`วัน(` + thWeekday + `|อาทิตย์)`), lastWeekday(true)},
+	{regexp.MustCompile(`(?:^|\D)(\d{1,2})(?:st|nd|rd|th)?\s*(\p{Thai}[\p{Thai}.]*|[a-z]{3,9}\.?)`), dayMonth(1, 2)},
+	{regexp.MustCompile(`\b([a-z]{3,9})\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b`), dayMonth(2, 1)},
+}
+
+// FindRelative resolves the first relative day in chat-style text against
+// now: today, yesterday, the day before ("เมื่อวานซืน"), "last Friday",
+// "วันศุกร์ที่แล้ว", "3 days ago", last week ("อาทิตย์ที่แล้ว", a week
+// back) or a day and month without a year. A time on the same or the next
+// line is kept. now must be in the user's location.
+func FindRelative(text string, now time.Time) (Date, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	lines := strings.Split(strings.ToLower(thaiDigits.Replace(text)), "\n")
+	for i, line := range lines {
+		t, ok := findRelativeDay(line, today)
+		if !ok {
+			continue
+		}
+		return withTime(t, lines[i:min(i+2, len(lines))]), true
+	}
+	return Date{}, false
+}
+
+func findRelativeDay(line string, today time.Time) (time.Time, bool) {
+	var best time.Time
+	at := -1
+	for _, r := range relativeRules {
+		for _, loc := range r.re.FindAllStringSubmatchIndex(line, -1) {
+			if at >= 0 && loc[0] >= at {
+				break
+			}
+			g := make([]string, len(loc)/2)
+			for j := range g {
+				if loc[2*j] >= 0 {
+					g[j] = line[loc[2*j]:loc[2*j+1]]
+				}
+			}
+			if t, ok := r.day(g, today); ok {
+				best, at = t, loc[0]
+				break
+			}
+		}
+	}
+	return best, at >= 0
+}
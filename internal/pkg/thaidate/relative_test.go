@@ -0,0 +1,45 @@
+package thaidate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindRelative(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, bangkok) // a Friday
+	tests := []struct {
+		text string
+		want string // "" when no day is found
+	}{
+		{"เมื่อวาน ข้าวมันไก่ 50", "2026-10-15"},
+		{"yesterday coffee 85", "2026-10-15"},
+		{"เมื่อวานซืน", "2026-10-14"},
+		{"the day before yesterday", "2026-10-14"},
+		{"last Friday taxi 120", "2026-10-09"},
+		{"friday lunch 60", "2026-10-16"},
+		{"วันศุกร์ที่แล้ว", "2026-10-09"},
+		{"อาทิตย์ที่แล้ว", "2026-10-09"},
+		{"last week", "2026-10-09"},
+		{"วันอาทิตย์ที่แล้ว", "2026-10-11"},
+		{"วันพุธ", "2026-10-14"},
+		{"วันพุธที่แล้ว", "2026-10-14"},
+		{"วันนี้ 14:30 กาแฟ 45", "2026-10-16T14:30:00+07:00"},
+		{"3 days ago", "2026-10-13"},
+		{"2 วันก่อน", "2026-10-14"},
+		{"๒ วันที่แล้ว", "2026-10-14"},
+		{"15 มี.ค.", "2026-03-15"},
+		{"dec 24", "2025-12-24"},
+		{"24th December", "2025-12-24"},
+		{"coffee 85", ""},
+	}
+	for _, tt := range tests {
+		d, ok := FindRelative(tt.text, now)
+		got := ""
+		if ok {
+			got = d.String()
+		}
+		if got != tt.want {
+			t.Errorf("FindRelative(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
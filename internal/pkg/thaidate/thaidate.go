@@ -0,0 +1,199 @@
+// Package thaidate finds and normalizes the dates printed on Thai receipts:
+// Buddhist Era and Common Era years, 2-digit years, Thai month names and
+// abbreviations, and Thai digits. It also resolves the relative days users
+// type in Thai or English ("เมื่อวาน", "last Friday").
+package thaidate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// beOffset is the difference between Buddhist Era and Common Era years.
+const beOffset = 543
+
+// Date is a point in time read from text. HasTime is false when only the
+// calendar date was printed; the time of day is then midnight.
+type Date struct {
+	Time    time.Time
+	HasTime bool
+}
+
+// String formats d as YYYY-MM-DD, or YYYY-MM-DDTHH:MM:SS+07:00 with a time.
+func (d Date) String() string {
+	if !d.HasTime {
+		return d.Time.Format(time.DateOnly)
+	}
+	return d.Time.Format(time.RFC3339)
+}
+
+// SameDay reports whether d and o fall on the same calendar date in o's
+// location.
+func (d Date) SameDay(o Date) bool {
+	y1, m1, d1 := d.Time.In(o.Time.Location()).Date()
+	y2, m2, d2 := o.Time.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+var thaiDigits = strings.NewReplacer("๐", "0", "๑", "1", "๒", "2", "๓", "3", "๔", "4", "๕", "5", "๖", "6", "๗", "7", "๘", "8", "๙", "9")
+
+// months maps month names and abbreviations, without dots and spaces and
+// lower-cased, to their number.
+var months = map[string]time.Month{
+	"มกราคม": 1, "กุมภาพันธ์": 2, "มีนาคม": 3, "เมษายน": 4, "พฤษภาคม": 5, "มิถุนายน": 6,
+	"กรกฎาคม": 7, "สิงหาคม": 8, "กันยายน": 9, "ตุลาคม": 10, "พฤศจิกายน": 11, "ธันวาคม": 12,
+	"มค": 1, "กพ": 2, "มีค": 3, "เมย": 4, "พค": 5, "มิย": 6, "กค": 7, "สค": 8, "กย": 9, "ตค": 10, "พย": 11, "ธค": 12,
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "sept": 9, "oct": 10, "nov": 11, "dec": 12,
+	"january": 1, "february": 2, "march": 3, "april": 4, "june": 6, "july": 7, "august": 8,
+	"september": 9, "october": 10, "november": 11, "december": 12,
+}
+
+var (
+	ymdRe   = regexp.MustCompile(`(?:^|\D)(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})(?:\D|$)`)
+	dmyRe   = regexp.MustCompile(`(?:^|\D)(\d{1,2})[/.-](\d{1,2})[/.-](\d{4}|\d{2})(?:\D|$)`)
+	dMonYRe = regexp.MustCompile(`(?:^|\D)(\d{1,2})[\s/-]*(\p{Thai}[\p{Thai}.\s]*?|[A-Za-z]{3,9}\.?)[\s/,-]*(\d{4}|\d{2})(?:\D|$)`)
+	// "14:22", "14:22:05", and "14.22 น." (a dot only with น., so amounts
+	// such as "12.50" are not read as times)
+	timeRe = regexp.MustCompile(`(?:^|\D)([01]?\d|2[0-3])(?::([0-5]\d)(?::([0-5]\d))?|\.([0-5]\d)\s*น)`)
+)
+
+// Find returns the first plausible date in text, with the time printed on
+// the same or the next line. Years are resolved against now, which must be
+// in the receipt's location: BE years are converted and 2-digit years
+// become whichever of the BE and CE reading is closest to now without
+// being in the future.
+func Find(text string, now time.Time) (Date, bool) {
+	lines := strings.Split(thaiDigits.Replace(text), "\n")
+	for i, line := range lines {
+		t, ok := findDay(line, now)
+		if !ok {
+			continue
+		}
+		return withTime(t, lines[i:min(i+2, len(lines))]), true
+	}
+	return Date{}, false
+}
+
+// withTime adds the first time found in lines to the day t.
+func withTime(t time.Time, lines []string) Date {
+	for _, l := range lines {
+		if h, m, s, ok := findTime(l); ok {
+			return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), h, m, s, 0, t.Location()), HasTime: true}
+		}
+	}
+	return Date{Time: t}
+}
+
+func findDay(line string, now time.Time) (time.Time, bool) {
+	if g := ymdRe.FindStringSubmatch(line); g != nil {
+		if t, ok := makeDate(g[1], monthNum(g[2]), g[3], now); ok {
+			return t, true
+		}
+	}
+	if g := dmyRe.FindStringSubmatch(line); g != nil {
+		if t, ok := makeDate(g[3], monthNum(g[2]), g[1], now); ok {
+			return t, true
+		}
+	}
+	if g := dMonYRe.FindStringSubmatch(line); g != nil {
+		key := strings.ToLower(strings.NewReplacer(".", "", " ", "").Replace(g[2]))
+		if m, ok := months[key]; ok {
+			if t, ok := makeDate(g[3], m, g[1], now); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func monthNum(s string) time.Month {
+	n, _ := strconv.Atoi(s)
+	return time.Month(n)
+}
+
+// makeDate validates the parts and resolves the year.
+func makeDate(year string, month time.Month, day string, now time.Time) (time.Time, bool) {
+	d, _ := strconv.Atoi(day)
+	y, _ := strconv.Atoi(year)
+	if month < 1 || month > 12 || d < 1 || d > 31 {
+		return time.Time{}, false
+	}
+
+	var candidates []int
+	switch {
+	case len(year) == 2:
+		candidates = []int{2000 + y, 2500 + y - beOffset}
+	case y > 2400:
+		candidates = []int{y - beOffset}
+	default:
+		candidates = []int{y}
+	}
+
+	var best time.Time
+	latest := now.AddDate(0, 0, 1) // receipts are not from the future
+	for _, cy := range candidates {
+		t := time.Date(cy, month, d, 0, 0, 0, 0, now.Location())
+		if t.Day() != d || t.After(latest) {
+			continue // 31/04, or a misread year
+		}
+		if best.IsZero() || now.Sub(t) < now.Sub(best) {
+			best = t
+		}
+	}
+	return best, !best.IsZero()
+}
+
+func findTime(line string) (h, m, s int, ok bool) {
+	g := timeRe.FindStringSubmatch(line)
+	if g == nil {
+		return 0, 0, 0, false
+	}
+	h, _ = strconv.Atoi(g[1])
+	if g[2] != "" {
+		m, _ = strconv.Atoi(g[2])
+		s, _ = strconv.Atoi(g[3])
+	} else {
+		m, _ = strconv.Atoi(g[4])
+	}
+	return h, m, s, true
+}
+
+// modelLayouts are the ISO-8601 forms the model is asked for, plus the
+// variants it writes anyway.
+var modelLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{time.RFC3339, true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02T15:04", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02 15:04", true},
+	{time.DateOnly, false},
+}
+
+// ParseISO reads a date written by the model. Times without an offset are
+// taken to be in loc. A Buddhist Era year passed straight through (2567)
+// is converted, and beFixed reports it.
+func ParseISO(s string, loc *time.Location) (d Date, beFixed bool, ok bool) {
+	s = strings.TrimSpace(thaiDigits.Replace(s))
+	// convert the year before parsing: 2567-02-29 is a valid date but
+	// 2567 is no leap year
+	if g := isoYearRe.FindStringSubmatch(s); g != nil {
+		if y, _ := strconv.Atoi(g[1]); y > 2400 {
+			s, beFixed = strconv.Itoa(y-beOffset)+g[2], true
+		}
+	}
+	for _, l := range modelLayouts {
+		t, err := time.ParseInLocation(l.layout, s, loc)
+		if err != nil {
+			continue
+		}
+		return Date{Time: t, HasTime: l.hasTime}, beFixed, true
+	}
+	return Date{}, false, false
+}
+
+var isoYearRe = regexp.MustCompile(`^(\d{4})(-.*)$`)
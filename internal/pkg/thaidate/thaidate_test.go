@@ -0,0 +1,72 @@
+package thaidate
+
+import (
+	"testing"
+	"time"
+)
+
+var bangkok = time.FixedZone("ICT", 7*60*60)
+
+func TestFind(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, bangkok)
+	tests := []struct {
+		text string
+		want string // "" when no date is found
+	}{
+		{"15/03/67", "2024-03-15"},
+		{"2567-03-15", "2024-03-15"},
+		{"15 มี.ค. 2567", "2024-03-15"},
+		{"15 มีนาคม 67", "2024-03-15"},
+		{"15-Mar-2024", "2024-03-15"},
+		{"15 March 2024", "2024-03-15"},
+		{"๑๕/๐๓/๒๕๖๗", "2024-03-15"},
+		{"15/03/25", "2025-03-15"},
+		{"2024/03/15", "2024-03-15"},
+		{"วันที่ 15/03/67 14:22", "2024-03-15T14:22:00+07:00"},
+		{"15/03/2024 14:22:05", "2024-03-15T14:22:05+07:00"},
+		{"15/03/2024\n09.05 น.", "2024-03-15T09:05:00+07:00"},
+		{"15/03/2024\nTOTAL 12.50", "2024-03-15"},
+		{"31/04/2024", ""},
+		{"TOTAL 12.50", ""},
+	}
+	for _, tt := range tests {
+		d, ok := Find(tt.text, now)
+		got := ""
+		if ok {
+			got = d.String()
+		}
+		if got != tt.want {
+			t.Errorf("Find(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseISO(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string // "" when it does not parse
+		beFixed bool
+	}{
+		{"2024-03-15", "2024-03-15", false},
+		{"2567-03-15", "2024-03-15", true},
+		{"2567-02-29", "2024-02-29", true},
+		{"2024-03-15T14:22:00+07:00", "2024-03-15T14:22:00+07:00", false},
+		{"2024-03-15T14:22", "2024-03-15T14:22:00+07:00", false},
+		{"2024-03-15 14:22:05", "2024-03-15T14:22:05+07:00", false},
+		{"2567-03-15T10:00:00", "2024-03-15T10:00:00+07:00", true},
+		{"๒๐๒๔-๐๓-๑๕", "2024-03-15", false},
+		{"2023-02-29", "", false},
+		{"yesterday", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		d, beFixed, ok := ParseISO(tt.in, bangkok)
+		got := ""
+		if ok {
+			got = d.String()
+		}
+		if got != tt.want || beFixed != tt.beFixed {
+			t.Errorf("ParseISO(%q) = %q, %v, want %q, %v", tt.in, got, beFixed, tt.want, tt.beFixed)
+		}
+	}
+}
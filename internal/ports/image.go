@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type ImagePort interface {
+	// Prepare returns the bytes to send for OCR. Inputs it does not handle
+	// (PDFs, unknown formats) come back unchanged.
+	Prepare(ctx context.Context, image []byte) ([]byte, error)
+}
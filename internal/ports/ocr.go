@@ -2,6 +2,14 @@ package ports
 
 import "context"
 
+// Fingerprinter is implemented by OCRPort and ImagePort adapters whose
+// output depends on their configuration (models, page caps, preprocessing
+// steps and thresholds). The fingerprint is part of the OCR cache key, so a
+// configuration change does not serve text cached under the old one.
+type Fingerprinter interface {
+	Fingerprint() string
+}
+
 type OCRPort interface {
 	// Returns extracted text from image bytes (expects valid image formats).
 	ExtractText(ctx context.Context, image []byte) (string, error)
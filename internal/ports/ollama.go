@@ -9,3 +9,25 @@ import (
 type OllamaPort interface {
 	ParseOcrResponseToJson(ctx context.Context, text string, categories []string) (*domain.Transaction, error)
 }
+
+// CacheKeyer is implemented by OllamaPort backends whose results may be
+// cached. The key has to cover everything that changes the output: the
+// preprocessed text, the categories, the model and the prompt version.
+type CacheKeyer interface {
+	CacheKey(text string, categories []string) string
+}
+
+// StreamingLLM is implemented by backends that can hand over transaction
+// items while the model is still generating. The returned transaction is
+// the final result and may differ from the streamed items (repair,
+// re-prompts).
+type StreamingLLM interface {
+	StreamTransaction(ctx context.Context, text string, categories []string, onItem func(domain.TransactionItem)) (*domain.Transaction, error)
+}
+
+// MultiTransactionLLM is implemented by backends that can split a message
+// listing several expenses ("lunch 120, taxi 80") into separate
+// transactions.
+type MultiTransactionLLM interface {
+	ParseTransactions(ctx context.Context, text string, categories []string) ([]domain.Entry, error)
+}
@@ -4,20 +4,95 @@ import (
 	"context"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/admission"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/flight"
 	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/ports"
 	aiwpb "github.com/cp25sy5-modjot/proto/gen/ai/v2"
 )
 
+// bangkok is the default timezone; Thailand has no daylight saving time.
+var bangkok = time.FixedZone("Asia/Bangkok", 7*60*60)
+
 type AIService struct {
 	aiwpb.UnimplementedAiWrapperServiceServer
 	ocr    ports.OCRPort
 	ollama ports.OllamaPort
+	health ports.HealthPort
+	// fallback parses text when the LLM cannot; nil disables it.
+	fallback ports.OllamaPort
+	// images prepares photos before OCR; nil sends them as uploaded.
+	images ports.ImagePort
+	limits UploadLimits
+	// currency is assumed when the text does not show one.
+	currency string
+	// loc is the timezone of dates printed without an offset.
+	loc *time.Location
+
+	// llmLimiter bounds concurrent LLM generations; nil means unlimited.
+	llmLimiter *admission.Limiter
+	// cache holds OCR text and parsed transactions; nil disables caching.
+	cache cache.Store
+
+	ocrFlight   flight.Group[string]
+	imageFlight flight.Group[imageResult]
+	textFlight  flight.Group[*domain.Transaction]
+}
+
+// Option configures optional AIService collaborators.
+type Option func(*AIService)
+
+// WithHealth makes the Check RPC answer from the given dependency health
+// source instead of always reporting healthy.
+func WithHealth(h ports.HealthPort) Option {
+	return func(s *AIService) { s.health = h }
+}
+
+// WithLLMLimiter queues LLM calls behind an admission limiter.
+func WithLLMLimiter(l *admission.Limiter) Option {
+	return func(s *AIService) { s.llmLimiter = l }
+}
+
+// WithCache caches OCR text by image hash and transactions by the backend's
+// cache key. Callers can bypass it with "cache-control" request metadata.
+func WithCache(c cache.Store) Option {
+	return func(s *AIService) { s.cache = c }
+}
+
+// WithFallback uses p, typically the rule-based parser, when the LLM backend
+// is down or returns unusable output. Requests shed by the LLM limiter are
+// refused rather than answered by p.
+func WithFallback(p ports.OllamaPort) Option {
+	return func(s *AIService) { s.fallback = p }
+}
+
+// WithDefaultCurrency sets the ISO 4217 code assumed when neither the text
+// nor the model names a currency (domain.DefaultCurrency otherwise).
+func WithDefaultCurrency(code string) Option {
+	return func(s *AIService) { s.currency = code }
+}
+
+// WithTimezone sets the timezone of receipt dates and times, which are
+// printed without an offset (Asia/Bangkok otherwise).
+func WithTimezone(loc *time.Location) Option {
+	return func(s *AIService) { s.loc = loc }
+}
+
+// WithImagePreprocessor runs uploads through p before OCR. The cache still
+// keys on the original bytes, so repeated uploads skip preprocessing too.
+func WithImagePreprocessor(p ports.ImagePort) Option {
+	return func(s *AIService) { s.images = p }
 }
 
-func NewAIService(ocr ports.OCRPort, ollama ports.OllamaPort) *AIService {
-	return &AIService{ocr: ocr, ollama: ollama}
+func NewAIService(ocr ports.OCRPort, ollama ports.OllamaPort, opts ...Option) *AIService {
+	s := &AIService{ocr: ocr, ollama: ollama, currency: domain.DefaultCurrency, loc: bangkok}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ===== gRPC Methods =====
@@ -28,20 +103,36 @@ func (s *AIService) Check(ctx context.Context, req *aiwpb.HealthCheckRequest) (*
 	if name == "" {
 		name = "ai-wrapper"
 	}
+	if s.health == nil {
+		return &aiwpb.HealthCheckResponse{
+			Healthy: true,
+			Message: "OK: " + name,
+		}, nil
+	}
+
+	// "ai-wrapper" is the whole service, anything else a dependency name
+	dep := name
+	if dep == "ai-wrapper" {
+		dep = ""
+	}
+	healthy, msg := s.health.Check(ctx, dep)
+	if dep == "" {
+		msg += ": " + name
+	}
 	return &aiwpb.HealthCheckResponse{
-		Healthy: true,
-		Message: "OK: " + name,
+		Healthy: healthy,
+		Message: msg,
 	}, nil
 }
 
 func (s *AIService) ExtractTextFromImage(ctx context.Context, req *aiwpb.ExtractTextRequest) (*aiwpb.ExtractTextResponse, error) {
 	log.Printf("ExtractTextFromImage called")
-	if len(req.GetImageData()) == 0 {
-		return nil, invalidArg("image_data is empty")
+	if err := s.checkUpload(req.GetImageData()); err != nil {
+		return nil, err
 	}
-	txt, err := s.ocr.ExtractText(ctx, req.GetImageData())
+	txt, err := s.extractText(ctx, req.GetImageData())
 	if err != nil {
-		return nil, invalidArg("image_data decode failed: " + err.Error())
+		return nil, toStatus(stageOCR, err)
 	}
 	return &aiwpb.ExtractTextResponse{ExtractedText: txt}, nil
 }
@@ -50,31 +141,32 @@ func (s *AIService) BuildTransactionFromText(ctx context.Context, req *aiwpb.Bui
 	log.Printf("BuildTransactionFromText called")
 	text := strings.TrimSpace(req.GetTextToAnalyze())
 	if text == "" {
-		return nil, invalidArg("text_to_analyze is empty")
+		return nil, invalidArg("text_to_analyze", "text_to_analyze is empty")
+	}
+	// v2 text is typed too: dated now when it names no day, whatever the
+	// model says, with relative days resolved against now
+	ctx, err := s.withReference(ctx, nil, "")
+	if err != nil {
+		return nil, err
 	}
-	//to be change to call ollama instead of parser
-	tr, err := s.ollama.ParseOcrResponseToJson(ctx, text, req.GetCategories())
+	tr, err := s.parseText(ctx, text, req.GetCategories())
 	if err != nil {
-		return nil, invalidArg("failed to parse text: " + err.Error())
+		return nil, toStatus(stageLLM, err)
 	}
+	reportSource(ctx, tr)
 	return toPB(tr), nil
 }
 
 func (s *AIService) BuildTransactionFromImage(ctx context.Context, req *aiwpb.BuildTransactionFromImageRequest) (*aiwpb.TransactionResponseV2, error) {
 	log.Printf("BuildTransactionFromImage called")
-	if len(req.GetImageData()) == 0 {
-		return nil, invalidArg("image_data is empty")
-	}
-	txt, err := s.ocr.ExtractText(ctx, req.GetImageData())
-	if err != nil {
-		return nil, invalidArg("image_data decode failed: " + err.Error())
+	if err := s.checkUpload(req.GetImageData()); err != nil {
+		return nil, err
 	}
-	txt = strings.TrimSpace(txt)
-	//to be change to call ollama instead of parser
-	tr, err := s.ollama.ParseOcrResponseToJson(ctx, txt, req.GetCategories())
+	tr, err := s.buildFromImage(ctx, req.GetImageData(), req.GetCategories())
 	if err != nil {
-		return nil, invalidArg("failed to parse text: " + err.Error())
+		return nil, err
 	}
+	reportSource(ctx, tr)
 	return toPB(tr), nil
 }
 
@@ -93,29 +185,10 @@ func buildTransactionItemsPB(items []domain.TransactionItem) []*aiwpb.Transactio
 	for _, item := range items {
 		pbItem := &aiwpb.TransactionItem{
 			Title:    item.Title,
-			Price:    item.Price,
+			Price:    item.Price.Float64(),
 			Category: item.Category,
 		}
 		pbItems = append(pbItems, pbItem)
 	}
 	return pbItems
 }
-
-func invalidArg(msg string) error {
-	// gRPC status code 3 (InvalidArgument)
-	return statusError(3, msg)
-}
-
-// minimal wrapper to avoid importing status/codes everywhere
-func statusError(code int, msg string) error {
-	// google.golang.org/grpc/status + codes would be typical; kept minimal here:
-	log.Printf("gRPC error %d: %s", code, msg)
-	return grpcErr{code: code, msg: msg}
-}
-
-type grpcErr struct {
-	code int
-	msg  string
-}
-
-func (e grpcErr) Error() string { return e.msg }
@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/media"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/progress"
+	aiwpbv3 "github.com/cp25sy5-modjot/ai-wrapper-service/proto/gen/ai/v3"
+	aiwpb "github.com/cp25sy5-modjot/proto/gen/ai/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// AIServiceV3 serves the ai.v3 RPCs on the same pipeline as AIService.
+type AIServiceV3 struct {
+	aiwpbv3.UnimplementedAiWrapperServiceServer
+	svc *AIService
+}
+
+func NewAIServiceV3(svc *AIService) *AIServiceV3 {
+	return &AIServiceV3{svc: svc}
+}
+
+// ===== gRPC Methods =====
+
+func (s *AIServiceV3) BuildTransactionFromText(ctx context.Context, req *aiwpbv3.BuildTransactionFromTextRequest) (*aiwpbv3.TransactionResponseV3, error) {
+	log.Printf("BuildTransactionFromText (v3) called")
+	text := strings.TrimSpace(req.GetTextToAnalyze())
+	if text == "" {
+		return nil, invalidArg("text_to_analyze", "text_to_analyze is empty")
+	}
+	ctx, err := s.svc.withReference(ctx, req.GetReferenceTime(), req.GetTimezone())
+	if err != nil {
+		return nil, err
+	}
+	tr, err := s.svc.parseText(ctx, text, req.GetCategories())
+	if err != nil {
+		return nil, toStatus(stageLLM, err)
+	}
+	reportSource(ctx, tr)
+	return toPBV3(tr), nil
+}
+
+func (s *AIServiceV3) BuildTransactionFromImage(ctx context.Context, req *aiwpb.BuildTransactionFromImageRequest) (*aiwpbv3.TransactionResponseV3, error) {
+	log.Printf("BuildTransactionFromImage (v3) called")
+	if err := s.svc.checkUpload(req.GetImageData()); err != nil {
+		return nil, err
+	}
+	tr, err := s.svc.buildFromImage(ctx, req.GetImageData(), req.GetCategories())
+	if err != nil {
+		return nil, err
+	}
+	reportSource(ctx, tr)
+	return toPBV3(tr), nil
+}
+
+func (s *AIServiceV3) UploadAndBuildTransaction(stream grpc.ClientStreamingServer[aiwpbv3.UploadRequest, aiwpbv3.TransactionResponseV3]) error {
+	log.Printf("UploadAndBuildTransaction called")
+	meta, data, err := s.receiveUpload(stream)
+	if err != nil {
+		return err
+	}
+	log.Printf("upload received: %q (%s), %d bytes", meta.GetFilename(), meta.GetContentType(), len(data))
+	if err := s.svc.checkUpload(data); err != nil {
+		return err
+	}
+
+	tr, err := s.svc.buildFromImage(stream.Context(), data, meta.GetCategories())
+	if err != nil {
+		return err
+	}
+	reportSource(stream.Context(), tr)
+	return stream.SendAndClose(toPBV3(tr))
+}
+
+func (s *AIServiceV3) BuildTransactionFromImageWithProgress(req *aiwpb.BuildTransactionFromImageRequest, stream grpc.ServerStreamingServer[aiwpbv3.ProgressEvent]) error {
+	log.Printf("BuildTransactionFromImageWithProgress called")
+	data := req.GetImageData()
+	if err := s.svc.checkUpload(data); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	send := func(ev *aiwpbv3.ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := stream.Send(ev); err != nil {
+			log.Printf("progress event not sent: %v", err)
+		}
+	}
+	ctx := progress.WithReporter(stream.Context(), func(ev progress.Event) {
+		send(toProgressPB(ev))
+	})
+
+	f, _ := media.Sniff(data)
+	progress.Report(ctx, progress.Event{Stage: progress.Received, Message: fmt.Sprintf("%s, %d bytes", f.Name, len(data))})
+
+	tr, err := s.svc.buildFromImage(ctx, data, req.GetCategories())
+	if err != nil {
+		st, _ := status.FromError(err)
+		send(&aiwpbv3.ProgressEvent{
+			Stage:     aiwpbv3.ProgressEvent_STAGE_FAILED,
+			Message:   st.Message(),
+			ErrorCode: int32(st.Code()),
+		})
+		return err
+	}
+	send(&aiwpbv3.ProgressEvent{
+		Stage:       aiwpbv3.ProgressEvent_STAGE_COMPLETED,
+		Transaction: toPBV3(tr),
+		Source:      sourceOf(tr),
+	})
+	return nil
+}
+
+func (s *AIServiceV3) StreamTransaction(req *aiwpbv3.StreamTransactionRequest, stream grpc.ServerStreamingServer[aiwpbv3.TransactionStreamEvent]) error {
+	log.Printf("StreamTransaction called")
+	ctx := stream.Context()
+
+	var text string
+	switch in := req.GetInput().(type) {
+	case *aiwpbv3.StreamTransactionRequest_ImageData:
+		if err := s.svc.checkUpload(in.ImageData); err != nil {
+			return err
+		}
+		txt, err := s.svc.extractText(ctx, in.ImageData)
+		if err != nil {
+			return toStatus(stageOCR, err)
+		}
+		text = strings.TrimSpace(txt)
+	case *aiwpbv3.StreamTransactionRequest_TextToAnalyze:
+		if text = strings.TrimSpace(in.TextToAnalyze); text == "" {
+			return invalidArg("text_to_analyze", "text_to_analyze is empty")
+		}
+		var err error
+		if ctx, err = s.svc.withReference(ctx, req.GetReferenceTime(), req.GetTimezone()); err != nil {
+			return err
+		}
+	default:
+		return invalidArg("input", "image_data or text_to_analyze is required")
+	}
+
+	// the final transaction settles the currency; until then items are
+	// labelled with the one the text shows, else the default
+	currency := domain.DetectCurrency(text)
+	if currency == "" {
+		currency = s.svc.currency
+	}
+	tr, err := s.svc.streamText(ctx, text, req.GetCategories(), func(item domain.TransactionItem) {
+		one := domain.Transaction{Items: []domain.TransactionItem{item}}
+		one.SetCurrency(currency)
+		ev := &aiwpbv3.TransactionStreamEvent{Event: &aiwpbv3.TransactionStreamEvent_Item{
+			Item: buildTransactionItemsPBV3(one.Currency, one.Items)[0],
+		}}
+		if err := stream.Send(ev); err != nil {
+			log.Printf("streamed item not sent: %v", err)
+		}
+	})
+	if err != nil {
+		return toStatus(stageLLM, err)
+	}
+	return stream.Send(&aiwpbv3.TransactionStreamEvent{
+		Event: &aiwpbv3.TransactionStreamEvent_Transaction{
+			Transaction: toPBV3(tr),
+		},
+		Source: sourceOf(tr),
+	})
+}
+
+func (s *AIServiceV3) BuildTransactionsFromText(ctx context.Context, req *aiwpbv3.BuildTransactionFromTextRequest) (*aiwpbv3.BuildTransactionsFromTextResponse, error) {
+	log.Printf("BuildTransactionsFromText called")
+	text := strings.TrimSpace(req.GetTextToAnalyze())
+	if text == "" {
+		return nil, invalidArg("text_to_analyze", "text_to_analyze is empty")
+	}
+	ctx, err := s.svc.withReference(ctx, req.GetReferenceTime(), req.GetTimezone())
+	if err != nil {
+		return nil, err
+	}
+	trs, err := s.svc.parseEntries(ctx, text, req.GetCategories())
+	if err != nil {
+		return nil, toStatus(stageLLM, err)
+	}
+	resp := &aiwpbv3.BuildTransactionsFromTextResponse{}
+	for _, tr := range trs {
+		resp.Transactions = append(resp.Transactions, toPBV3(tr))
+	}
+	return resp, nil
+}
+
+// ===== helpers =====
+
+var paymentMethods = map[string]aiwpbv3.PaymentMethod{
+	domain.PaymentCash:    aiwpbv3.PaymentMethod_PAYMENT_METHOD_CASH,
+	domain.PaymentCard:    aiwpbv3.PaymentMethod_PAYMENT_METHOD_CARD,
+	domain.PaymentQR:      aiwpbv3.PaymentMethod_PAYMENT_METHOD_QR,
+	domain.PaymentEWallet: aiwpbv3.PaymentMethod_PAYMENT_METHOD_E_WALLET,
+}
+
+func toPBV3(t *domain.Transaction) *aiwpbv3.TransactionResponseV3 {
+	return &aiwpbv3.TransactionResponseV3{
+		Title:              t.Title,
+		Date:               t.Date,
+		Items:              buildTransactionItemsPBV3(t.Currency, t.Items),
+		Currency:           t.Currency,
+		Subtotal:           toFloatPB(t.Subtotal),
+		DiscountTotal:      toFloatPB(t.DiscountTotal),
+		ServiceCharge:      toFloatPB(t.ServiceCharge),
+		VatAmount:          toFloatPB(t.VATAmount),
+		VatRate:            t.VATRate,
+		GrandTotal:         toFloatPB(t.GrandTotal),
+		SubtotalMoney:      toOptionalMoneyPB(t.Currency, t.Subtotal),
+		DiscountTotalMoney: toOptionalMoneyPB(t.Currency, t.DiscountTotal),
+		ServiceChargeMoney: toOptionalMoneyPB(t.Currency, t.ServiceCharge),
+		VatAmountMoney:     toOptionalMoneyPB(t.Currency, t.VATAmount),
+		GrandTotalMoney:    toOptionalMoneyPB(t.Currency, t.GrandTotal),
+		PaymentMethod:      paymentMethods[t.PaymentMethod],
+		MerchantTaxId:      t.MerchantTaxID,
+		ReceiptNumber:      t.ReceiptNumber,
+		Source:             sourceOf(t),
+		Reconciliation:     toReconciliationPB(t.Currency, domain.Reconcile(t)),
+	}
+}
+
+func buildTransactionItemsPBV3(currency string, items []domain.TransactionItem) []*aiwpbv3.TransactionItemV3 {
+	var pbItems []*aiwpbv3.TransactionItemV3
+	for _, item := range items {
+		pbItems = append(pbItems, &aiwpbv3.TransactionItemV3{
+			Title:          item.Title,
+			Price:          item.Price.Float64(),
+			Category:       item.Category,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice.Float64(),
+			Unit:           item.Unit,
+			PriceMoney:     toMoneyPB(currency, item.Price),
+			UnitPriceMoney: toMoneyPB(currency, item.UnitPrice),
+		})
+	}
+	return pbItems
+}
+
+func toReconciliationPB(currency string, r *domain.Reconciliation) *aiwpbv3.Reconciliation {
+	if r == nil {
+		return nil
+	}
+	pb := &aiwpbv3.Reconciliation{
+		Consistent:         r.Consistent,
+		ItemsTotal:         r.ItemsTotal.Float64(),
+		ExpectedTotal:      r.ExpectedTotal.Float64(),
+		Discrepancy:        r.Discrepancy.Float64(),
+		ItemsTotalMoney:    toMoneyPB(currency, r.ItemsTotal),
+		ExpectedTotalMoney: toMoneyPB(currency, r.ExpectedTotal),
+		DiscrepancyMoney:   toMoneyPB(currency, r.Discrepancy),
+	}
+	for _, sus := range r.Suspects {
+		pb.Suspects = append(pb.Suspects, &aiwpbv3.Reconciliation_Suspect{Index: int32(sus.Index), Reason: sus.Reason})
+	}
+	return pb
+}
+
+func toMoneyPB(currency string, a domain.Amount) *aiwpbv3.Money {
+	return &aiwpbv3.Money{Currency: currency, MinorUnits: a.Minor, Exponent: int32(a.Exp)}
+}
+
+func toOptionalMoneyPB(currency string, a *domain.Amount) *aiwpbv3.Money {
+	if a == nil {
+		return nil
+	}
+	return toMoneyPB(currency, *a)
+}
+
+func toFloatPB(a *domain.Amount) *float64 {
+	if a == nil {
+		return nil
+	}
+	f := a.Float64()
+	return &f
+}
+
+var progressStages = map[progress.Stage]aiwpbv3.ProgressEvent_Stage{
+	progress.Received:      aiwpbv3.ProgressEvent_STAGE_RECEIVED,
+	progress.Preprocessed:  aiwpbv3.ProgressEvent_STAGE_PREPROCESSED,
+	progress.OCRStarted:    aiwpbv3.ProgressEvent_STAGE_OCR_STARTED,
+	progress.OCRRetrying:   aiwpbv3.ProgressEvent_STAGE_OCR_RETRYING,
+	progress.OCRFinished:   aiwpbv3.ProgressEvent_STAGE_OCR_FINISHED,
+	progress.LLMGenerating: aiwpbv3.ProgressEvent_STAGE_LLM_GENERATING,
+}
+
+func toProgressPB(ev progress.Event) *aiwpbv3.ProgressEvent {
+	pb := &aiwpbv3.ProgressEvent{
+		Stage:         progressStages[ev.Stage],
+		Message:       ev.Message,
+		Attempt:       int32(ev.Attempt),
+		RetryInMs:     ev.RetryIn.Milliseconds(),
+		ExtractedText: ev.Text,
+		OcrTotalPages: int32(ev.TotalPages),
+	}
+	for _, p := range ev.FailedPages {
+		pb.OcrFailedPages = append(pb.OcrFailedPages, int32(p))
+	}
+	return pb
+}
+
+// receiveUpload reads the metadata message and then the chunks, refusing the
+// upload as soon as it grows past the byte limit.
+func (s *AIServiceV3) receiveUpload(stream grpc.ClientStreamingServer[aiwpbv3.UploadRequest, aiwpbv3.TransactionResponseV3]) (*aiwpbv3.UploadMetadata, []byte, error) {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, invalidArg("metadata", "upload stream is empty")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	meta := first.GetMetadata()
+	if meta == nil {
+		return nil, nil, invalidArg("metadata", "the first message must carry metadata")
+	}
+
+	limit := int64(s.svc.limits.MaxBytes)
+	if limit > 0 && meta.GetTotalSize() > limit {
+		return nil, nil, rejectInput("INPUT_TOO_LARGE", fmt.Sprintf("upload is %d bytes, limit is %d", meta.GetTotalSize(), limit))
+	}
+	var buf bytes.Buffer
+	if n := meta.GetTotalSize(); n > 0 && limit > 0 {
+		// total_size is the client's word; without a limit it is not trusted
+		// with an allocation
+		buf.Grow(int(min(n, limit)))
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return meta, buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if msg.GetMetadata() != nil {
+			return nil, nil, invalidArg("metadata", "metadata may only be sent in the first message")
+		}
+		chunk := msg.GetChunk()
+		if limit > 0 && int64(buf.Len()+len(chunk)) > limit {
+			return nil, nil, rejectInput("INPUT_TOO_LARGE", fmt.Sprintf("upload exceeds the limit of %d bytes", limit))
+		}
+		buf.Write(chunk)
+	}
+}
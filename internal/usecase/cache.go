@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"strings"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/ports"
+	"google.golang.org/grpc/metadata"
+)
+
+// cacheStats counts ocr_/tx_ hits and misses, exported via expvar.
+var cacheStats = expvar.NewMap("cache")
+
+// cachePolicy reads the "cache-control" request metadata: "no-cache" skips
+// the lookup but stores the fresh result, "no-store" bypasses the cache.
+func cachePolicy(ctx context.Context) (read, write bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	read, write = true, true
+	for _, v := range md.Get("cache-control") {
+		for _, directive := range strings.Split(v, ",") {
+			switch strings.TrimSpace(strings.ToLower(directive)) {
+			case "no-cache":
+				read = false
+			case "no-store":
+				read, write = false, false
+			}
+		}
+	}
+	return read, write
+}
+
+// cachedText returns OCR text cached for the SHA-256 of the image bytes and
+// the OCR and preprocessing configuration.
+func (s *AIService) cachedText(ctx context.Context, data []byte, extract func() (string, error)) (string, error) {
+	if s.cache == nil {
+		return extract()
+	}
+	read, write := cachePolicy(ctx)
+	key := cache.Key("ocr", fingerprint(s.ocr), fingerprint(s.images), cache.HashBytes(data))
+
+	if read {
+		if val, ok := s.cache.Get(key); ok {
+			cacheStats.Add("ocr_hits", 1)
+			return string(val), nil
+		}
+		cacheStats.Add("ocr_misses", 1)
+	}
+
+	txt, err := extract()
+	// partial results are not cached so a later upload can recover the failed pages
+	if err == nil && write {
+		s.cache.Set(key, []byte(txt))
+	}
+	return txt, err
+}
+
+// fingerprint is the configuration fingerprint of an adapter, "" when it
+// has none.
+func fingerprint(adapter any) string {
+	if f, ok := adapter.(ports.Fingerprinter); ok {
+		return f.Fingerprint()
+	}
+	return ""
+}
+
+// cachedTransaction returns a parsed transaction cached under the backend's
+// key for text and categories. Backends without a key are not cached.
+func (s *AIService) cachedTransaction(ctx context.Context, text string, categories []string, parse func() (*domain.Transaction, error)) (*domain.Transaction, error) {
+	keyer, ok := s.ollama.(ports.CacheKeyer)
+	if s.cache == nil || !ok {
+		return parse()
+	}
+	read, write := cachePolicy(ctx)
+	key := keyer.CacheKey(text, categories)
+
+	if read {
+		if val, ok := s.cache.Get(key); ok {
+			var tr domain.Transaction
+			if err := json.Unmarshal(val, &tr); err == nil {
+				cacheStats.Add("tx_hits", 1)
+				return &tr, nil
+			}
+		}
+		cacheStats.Add("tx_misses", 1)
+	}
+
+	tr, err := parse()
+	if err == nil && write {
+		if val, err := json.Marshal(tr); err == nil {
+			s.cache.Set(key, val)
+		}
+	}
+	return tr, err
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+)
+
+// fakeOCR counts calls and reports a configuration fingerprint.
+type fakeOCR struct {
+	config string
+	calls  int
+}
+
+func (f *fakeOCR) ExtractText(ctx context.Context, image []byte) (string, error) {
+	f.calls++
+	return "text", nil
+}
+
+func (f *fakeOCR) Fingerprint() string { return f.config }
+
+// fakeImages is an image preprocessor that changes nothing.
+type fakeImages struct{ config string }
+
+func (f *fakeImages) Prepare(ctx context.Context, image []byte) ([]byte, error) { return image, nil }
+func (f *fakeImages) Fingerprint() string                                       { return f.config }
+
+func TestOCRCacheKeyCoversConfig(t *testing.T) {
+	ocr := &fakeOCR{config: "max_pdf_pages=10"}
+	images := &fakeImages{config: "gate=off"}
+	svc := NewAIService(ocr, nil, WithCache(cache.NewLRU(16, time.Hour)), WithImagePreprocessor(images))
+	extract := func() (string, error) { return svc.ocr.ExtractText(context.Background(), nil) }
+	upload := []byte("same upload")
+
+	steps := []struct {
+		name      string
+		change    func()
+		wantCalls int
+	}{
+		{"first upload", func() {}, 1},
+		{"same config hits", func() {}, 1},
+		{"page cap changed", func() { ocr.config = "max_pdf_pages=20" }, 2},
+		{"preprocessing changed", func() { images.config = "gate=on" }, 3},
+		{"new config hits", func() {}, 3},
+	}
+	for _, st := range steps {
+		st.change()
+		if _, err := svc.cachedText(context.Background(), upload, extract); err != nil {
+			t.Fatalf("%s: %v", st.name, err)
+		}
+		if ocr.calls != st.wantCalls {
+			t.Errorf("%s: %d OCR calls, want %d", st.name, ocr.calls, st.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is the ErrorInfo domain attached to every error we return.
+const errorDomain = "ai-wrapper.modjot"
+
+// pipeline stages, reported in ErrorInfo metadata
+const (
+	stageOCR = "ocr"
+	stageLLM = "llm"
+)
+
+// defaultRetryDelay is advertised when the upstream did not say how long to wait.
+const defaultRetryDelay = 5 * time.Second
+
+// toStatus maps a pipeline failure to a gRPC status with error details so
+// clients can decide whether and when to retry.
+func toStatus(stage string, err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return err
+	}
+
+	var (
+		code    codes.Code
+		reason  string
+		details []protoadapt.MessageV1
+		quality *domain.QualityError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code, reason = codes.DeadlineExceeded, "DEADLINE_EXCEEDED"
+	case errors.Is(err, context.Canceled):
+		code, reason = codes.Canceled, "CANCELLED"
+	case errors.Is(err, domain.ErrOverloaded):
+		code, reason = codes.ResourceExhausted, "OVERLOADED"
+		details = append(details, retryInfo(err))
+	case errors.Is(err, domain.ErrRateLimited):
+		code, reason = codes.ResourceExhausted, "UPSTREAM_RATE_LIMITED"
+		details = append(details, retryInfo(err))
+	case errors.Is(err, domain.ErrUnavailable):
+		code, reason = codes.Unavailable, "UPSTREAM_UNAVAILABLE"
+	case errors.Is(err, domain.ErrInvalidInput):
+		code, reason = codes.InvalidArgument, "INPUT_REJECTED"
+		// name the field only when the error knows which one it was
+		var fe domain.InvalidFielder
+		if errors.As(err, &fe) && fe.InvalidField() != "" {
+			details = append(details, &errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: fe.InvalidField(), Description: err.Error()},
+				},
+			})
+		}
+	case errors.As(err, &quality):
+		// the client should ask the user to retake the photo
+		code, reason = codes.FailedPrecondition, quality.Reason
+		details = append(details, &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: quality.Reason, Subject: "image_data", Description: err.Error()},
+			},
+		})
+	case errors.Is(err, domain.ErrInvalidOutput):
+		code, reason = codes.Internal, "INVALID_MODEL_OUTPUT"
+	default:
+		code, reason = codes.Internal, "INTERNAL"
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"stage": stage},
+	}
+	if quality != nil {
+		info.Metadata["metric"] = quality.Metric
+		info.Metadata["value"] = strconv.FormatFloat(quality.Value, 'f', 1, 64)
+		info.Metadata["threshold"] = strconv.FormatFloat(quality.Threshold, 'f', 1, 64)
+	}
+	return withDetails(code, stage+": "+err.Error(), append([]protoadapt.MessageV1{info}, details...)...)
+}
+
+func retryInfo(err error) *errdetails.RetryInfo {
+	delay := defaultRetryDelay
+	var rd domain.RetryDelayer
+	if errors.As(err, &rd) && rd.RetryDelay() > 0 {
+		delay = rd.RetryDelay()
+	}
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+}
+
+func invalidArg(field, msg string) error {
+	return withDetails(codes.InvalidArgument, msg, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: msg},
+		},
+	})
+}
+
+// rejectInput is an InvalidArgument about image_data with a machine-readable
+// reason.
+func rejectInput(reason, msg string) error {
+	return withDetails(codes.InvalidArgument, msg,
+		&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain},
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "image_data", Description: msg},
+			},
+		})
+}
+
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	log.Printf("gRPC error %s: %s", code, msg)
+	st := status.New(code, msg)
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rejectedField is an upstream rejection of one request field.
+type rejectedField string
+
+func (e rejectedField) Error() string        { return "rejected " + string(e) }
+func (e rejectedField) Is(target error) bool { return target == domain.ErrInvalidInput }
+func (e rejectedField) InvalidField() string { return string(e) }
+
+func TestToStatusInvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		field string // "" when no BadRequest detail is expected
+	}{
+		{"field known", rejectedField("image_data"), "image_data"},
+		{"wrapped", fmt.Errorf("ocr: %w", rejectedField("image_data")), "image_data"},
+		{"text field", rejectedField("text_to_analyze"), "text_to_analyze"},
+		{"field unknown", fmt.Errorf("model refused: %w", domain.ErrInvalidInput), ""},
+	}
+	for _, tt := range tests {
+		st := status.Convert(toStatus(stageLLM, tt.err))
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("%s: code = %v, want InvalidArgument", tt.name, st.Code())
+		}
+		var fields []string
+		for _, d := range st.Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				for _, v := range br.FieldViolations {
+					fields = append(fields, v.Field)
+				}
+			}
+		}
+		switch {
+		case tt.field == "" && len(fields) > 0:
+			t.Errorf("%s: field violations %v, want none", tt.name, fields)
+		case tt.field != "" && (len(fields) != 1 || fields[0] != tt.field):
+			t.Errorf("%s: field violations %v, want [%s]", tt.name, fields, tt.field)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/media"
+)
+
+// UploadLimits bounds what clients may send as image_data; zero means no limit.
+type UploadLimits struct {
+	MaxBytes  int
+	MaxPixels int
+}
+
+// WithUploadLimits rejects uploads over the byte or pixel-count limits
+// before any of them is decoded.
+func WithUploadLimits(l UploadLimits) Option {
+	return func(s *AIService) { s.limits = l }
+}
+
+// checkUpload sniffs image_data and enforces the upload limits. Pixel counts
+// come from the image header, so decompression bombs never reach a decoder.
+func (s *AIService) checkUpload(data []byte) error {
+	if len(data) == 0 {
+		return invalidArg("image_data", "image_data is empty")
+	}
+	if s.limits.MaxBytes > 0 && len(data) > s.limits.MaxBytes {
+		return rejectInput("INPUT_TOO_LARGE", fmt.Sprintf("image_data is %d bytes, limit is %d", len(data), s.limits.MaxBytes))
+	}
+
+	f, ok := media.Sniff(data)
+	if !ok {
+		return rejectInput("UNSUPPORTED_FORMAT", "image_data is not a JPEG, PNG, WebP, HEIC or PDF file")
+	}
+	if !f.IsImage() || s.limits.MaxPixels <= 0 {
+		return nil
+	}
+
+	w, h, err := media.Dimensions(data, f)
+	switch {
+	case err != nil && f == media.HEIC:
+		// HEIC is never decoded here; let the OCR API judge it
+		return nil
+	case err != nil:
+		return rejectInput("CORRUPT_IMAGE", fmt.Sprintf("image_data is not a readable %s image: %v", f.Name, err))
+	case uint64(w)*uint64(h) > uint64(s.limits.MaxPixels):
+		// in uint64, where two header dimensions cannot overflow
+		return rejectInput("IMAGE_TOO_LARGE", fmt.Sprintf("image is %dx%d pixels, limit is %d pixels", w, h, s.limits.MaxPixels))
+	}
+	return nil
+}
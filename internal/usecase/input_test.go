@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func pngBytes(w, h int) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)))
+	return buf.Bytes()
+}
+
+// heicBytes is a HEIC header with one image spatial extent box.
+func heicBytes(w, h uint32) []byte {
+	box := make([]byte, 20)
+	binary.BigEndian.PutUint32(box, 20)
+	copy(box[4:], "ispe")
+	binary.BigEndian.PutUint32(box[12:], w)
+	binary.BigEndian.PutUint32(box[16:], h)
+	return append([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"), box...)
+}
+
+// reasonOf returns the ErrorInfo reason of a gRPC status error.
+func reasonOf(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestCheckUpload(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits UploadLimits
+		data   []byte
+		want   string // ErrorInfo reason, "" when accepted
+	}{
+		{"png within limits", UploadLimits{MaxBytes: 1 << 20, MaxPixels: 10000}, pngBytes(100, 100), ""},
+		{"no limits", UploadLimits{}, pngBytes(100, 100), ""},
+		{"too many bytes", UploadLimits{MaxBytes: 10}, pngBytes(100, 100), "INPUT_TOO_LARGE"},
+		{"too many pixels", UploadLimits{MaxPixels: 9999}, pngBytes(100, 100), "IMAGE_TOO_LARGE"},
+		{"unknown format", UploadLimits{}, []byte("GIF89a"), "UNSUPPORTED_FORMAT"},
+		{"corrupt png", UploadLimits{MaxPixels: 10000}, pngBytes(100, 100)[:12], "CORRUPT_IMAGE"},
+		{"pdf has no pixel count", UploadLimits{MaxPixels: 1}, []byte("%PDF-1.7\n"), ""},
+		{"heic without extent", UploadLimits{MaxPixels: 1}, heicBytes(0, 0), ""},
+		{"heic within limits", UploadLimits{MaxPixels: 50_000_000}, heicBytes(4032, 3024), ""},
+		// each side is under a large limit but the product wraps a signed int64
+		{"heic whose area overflows", UploadLimits{MaxPixels: 1 << 40}, heicBytes(0xFFFFFFFF, 0xFFFFFFFF), "IMAGE_TOO_LARGE"},
+	}
+	for _, tt := range tests {
+		s := NewAIService(nil, nil, WithUploadLimits(tt.limits))
+		err := s.checkUpload(tt.data)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: %v, want accepted", tt.name, err)
+		case tt.want != "" && (status.Code(err) != codes.InvalidArgument || reasonOf(err) != tt.want):
+			t.Errorf("%s: err = %v (reason %q), want InvalidArgument %s", tt.name, err, reasonOf(err), tt.want)
+		}
+	}
+
+	s := NewAIService(nil, nil)
+	if err := s.checkUpload(nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty upload: err = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/cache"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/flight"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/progress"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/thaidate"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/ports"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// ===== pipeline stages shared by the RPCs =====
+//
+// Identical concurrent work (same image bytes, or same text and categories)
+// is coalesced into one execution through the flight groups on AIService.
+
+// coalesced counts callers that joined work already in flight, by stage.
+var coalesced = expvar.NewMap("coalesced")
+
+// dateFixes counts model dates corrected against the receipt text.
+var dateFixes = expvar.NewMap("date_fixes")
+
+// fallbacks counts answers from the fallback parser ("used") and its failures.
+var fallbacks = expvar.NewMap("llm_fallback")
+
+// imageResult is what one image pipeline execution hands to every caller.
+type imageResult struct {
+	tr      *domain.Transaction
+	partial *domain.PartialOCRError
+}
+
+// flightKey identifies work; the cache policy is part of it so a caller
+// bypassing the cache never gets a result read from it.
+func flightKey(ctx context.Context, kind string, parts ...string) string {
+	read, write := cachePolicy(ctx)
+	return cache.Key(append([]string{kind, fmt.Sprint(read, write)}, parts...)...)
+}
+
+// coalesce runs fn through g, sharing one execution among identical callers.
+// Callers listening for progress run fn on their own: events go to the
+// context of whoever started the work, so they cannot be shared.
+func coalesce[T any](ctx context.Context, g *flight.Group[T], stage, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if progress.Enabled(ctx) {
+		return fn(ctx)
+	}
+	v, shared, err := g.Do(ctx, key, fn)
+	if shared {
+		coalesced.Add(stage, 1)
+	}
+	return v, err
+}
+
+// buildFromImage runs OCR and the LLM over an image or PDF. Errors are
+// already gRPC statuses labelled with the failing stage.
+func (s *AIService) buildFromImage(ctx context.Context, data []byte, categories []string) (*domain.Transaction, error) {
+	key := flightKey(ctx, "image", cache.HashBytes(data), strings.Join(categories, "\x1f"))
+	res, err := coalesce(ctx, &s.imageFlight, "image", key, func(ctx context.Context) (imageResult, error) {
+		txt, partial, err := s.ocrText(ctx, data)
+		if err != nil {
+			return imageResult{}, toStatus(stageOCR, err)
+		}
+		flight.SetStage(ctx, stageLLM)
+		tr, err := s.parseText(ctx, strings.TrimSpace(txt), categories)
+		if err != nil {
+			return imageResult{}, toStatus(stageLLM, err)
+		}
+		return imageResult{tr: tr, partial: partial}, nil
+	})
+	if err != nil {
+		// errors from the work are statuses already; this labels a caller
+		// that gave up by the stage the work was in
+		stage := stageOCR
+		var abandoned *flight.AbandonedError
+		if errors.As(err, &abandoned) && abandoned.Stage != "" {
+			stage = abandoned.Stage
+		}
+		return nil, toStatus(stage, err)
+	}
+	if res.partial != nil {
+		reportFailedPages(ctx, res.partial)
+	}
+	return res.tr, nil
+}
+
+// extractText runs OCR over an image or PDF. When only some PDF pages fail
+// the text of the remaining pages is kept and the failed page numbers are
+// sent back in the "x-ocr-failed-pages" response header.
+func (s *AIService) extractText(ctx context.Context, data []byte) (string, error) {
+	txt, partial, err := s.ocrText(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	if partial != nil {
+		reportFailedPages(ctx, partial)
+	}
+	return txt, nil
+}
+
+// ocrText is the coalesced and cached OCR call. A partial PDF result comes
+// back as text plus the report of the failed pages.
+func (s *AIService) ocrText(ctx context.Context, data []byte) (string, *domain.PartialOCRError, error) {
+	key := flightKey(ctx, "ocr", cache.HashBytes(data))
+	txt, err := coalesce(ctx, &s.ocrFlight, "ocr", key, func(ctx context.Context) (string, error) {
+		// answer from the cache when the same bytes were seen before
+		return s.cachedText(ctx, data, func() (string, error) {
+			img, err := s.prepareImage(ctx, data)
+			if err != nil {
+				return "", err
+			}
+			progress.Report(ctx, progress.Event{Stage: progress.OCRStarted})
+			return s.ocr.ExtractText(ctx, img)
+		})
+	})
+
+	var partial *domain.PartialOCRError
+	if errors.As(err, &partial) && txt != "" {
+		log.Printf("OCR partial result: %v", partial)
+		progress.Report(ctx, progress.Event{
+			Stage:       progress.OCRFinished,
+			Message:     partial.Error(),
+			Text:        txt,
+			TotalPages:  partial.TotalPages,
+			FailedPages: partial.FailedPageNumbers(),
+		})
+		return txt, partial, nil
+	}
+	if err == nil {
+		progress.Report(ctx, progress.Event{Stage: progress.OCRFinished, Text: txt})
+	}
+	return txt, nil, err
+}
+
+// prepareImage runs the optional preprocessing stage ahead of OCR.
+func (s *AIService) prepareImage(ctx context.Context, data []byte) ([]byte, error) {
+	if s.images == nil {
+		return data, nil
+	}
+	img, err := s.images.Prepare(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, progress.Event{
+		Stage:   progress.Preprocessed,
+		Message: fmt.Sprintf("%d -> %d bytes", len(data), len(img)),
+	})
+	return img, nil
+}
+
+// reportFailedPages sends the pages whose OCR failed in the
+// "x-ocr-total-pages" and "x-ocr-failed-pages" response headers. Progress
+// streams have sent events by now, so they get them in the OCRFinished
+// event instead.
+func reportFailedPages(ctx context.Context, partial *domain.PartialOCRError) {
+	if progress.Enabled(ctx) {
+		return
+	}
+	pages := make([]string, 0, len(partial.Failed))
+	for _, p := range partial.FailedPageNumbers() {
+		pages = append(pages, strconv.Itoa(p))
+	}
+	md := metadata.Pairs(
+		"x-ocr-total-pages", strconv.Itoa(partial.TotalPages),
+		"x-ocr-failed-pages", strings.Join(pages, ","),
+	)
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.Printf("failed to set OCR page header: %v", err)
+	}
+}
+
+// parseText turns OCR or user text into a transaction. Cache hits return
+// right away; misses wait for an LLM slot when a limiter is configured.
+// Callers sharing a flight may have different reference times, so each
+// settles its own copy of the result.
+func (s *AIService) parseText(ctx context.Context, text string, categories []string) (*domain.Transaction, error) {
+	key := flightKey(ctx, "text", cache.HashBytes([]byte(text)), strings.Join(categories, "\x1f"))
+	tr, err := coalesce(ctx, &s.textFlight, "text", key, func(ctx context.Context) (*domain.Transaction, error) {
+		tr, err := s.cachedTransaction(ctx, text, categories, func() (*domain.Transaction, error) {
+			return s.generate(ctx, func() (*domain.Transaction, error) {
+				return s.ollama.ParseOcrResponseToJson(ctx, text, categories)
+			})
+		})
+		return s.orFallback(ctx, text, categories, tr, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tr = tr.Clone()
+	s.settle(ctx, text, tr)
+	return tr, nil
+}
+
+// streamText is parseText for callers that want the items while they are
+// generated. It is not coalesced. Cache hits, and backends that cannot
+// stream, hand over all items at once when the transaction is ready.
+func (s *AIService) streamText(ctx context.Context, text string, categories []string, onItem func(domain.TransactionItem)) (*domain.Transaction, error) {
+	st, ok := s.ollama.(ports.StreamingLLM)
+	streamed, sent := false, false
+	emit := func(item domain.TransactionItem) {
+		sent = true
+		onItem(item)
+	}
+	tr, err := s.cachedTransaction(ctx, text, categories, func() (*domain.Transaction, error) {
+		return s.generate(ctx, func() (*domain.Transaction, error) {
+			if !ok {
+				return s.ollama.ParseOcrResponseToJson(ctx, text, categories)
+			}
+			streamed = true
+			return st.StreamTransaction(ctx, text, categories, emit)
+		})
+	})
+	if err != nil && !sent {
+		// items already sent cannot be taken back, so only fall back before
+		tr, err = s.orFallback(ctx, text, categories, tr, err)
+		streamed = false
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.settle(ctx, text, tr)
+	if !streamed {
+		for _, item := range tr.Items {
+			onItem(item)
+		}
+	}
+	return tr, nil
+}
+
+// parseEntries splits typed text listing several expenses into
+// transactions. It is neither coalesced nor cached, and has no fallback:
+// the rules parser reads one receipt. Each transaction's date and currency
+// come from its own part of the text.
+func (s *AIService) parseEntries(ctx context.Context, text string, categories []string) ([]*domain.Transaction, error) {
+	multi, ok := s.ollama.(ports.MultiTransactionLLM)
+	if !ok {
+		return nil, withDetails(codes.Unimplemented, "the configured LLM backend cannot split text into transactions",
+			&errdetails.ErrorInfo{Reason: "UNSUPPORTED_BY_BACKEND", Domain: errorDomain})
+	}
+	var entries []domain.Entry
+	_, err := s.generate(ctx, func() (*domain.Transaction, error) {
+		var err error
+		entries, err = multi.ParseTransactions(ctx, text, categories)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*domain.Transaction, 0, len(entries))
+	for _, e := range entries {
+		part := e.Text
+		if part == "" {
+			part = text
+		}
+		// "lunch 120 baht, coffee $5": each part's own sign first
+		s.settleCurrency(e.Transaction, part, text)
+		s.settleDate(ctx, part, e.Transaction)
+		out = append(out, e.Transaction)
+	}
+	return out, nil
+}
+
+// settle checks what the model said against text deterministically: the
+// currency and the date. tr must not be shared yet.
+func (s *AIService) settle(ctx context.Context, text string, tr *domain.Transaction) {
+	s.settleCurrency(tr, text)
+	s.settleDate(ctx, text, tr)
+}
+
+// settleCurrency sets tr's currency to the one shown by the first of texts
+// that shows one, else the model's answer, else the default, and brings the
+// amounts to its minor units.
+func (s *AIService) settleCurrency(tr *domain.Transaction, texts ...string) {
+	var code string
+	for _, text := range texts {
+		if code = domain.DetectCurrency(text); code != "" {
+			break
+		}
+	}
+	if code == "" {
+		code = strings.ToUpper(strings.TrimSpace(tr.Currency))
+		if _, ok := domain.CurrencyExponent(code); !ok {
+			code = s.currency
+		}
+	}
+	tr.SetCurrency(code)
+}
+
+// orFallback hands the text to the fallback parser when the LLM failed in a
+// way another attempt would not fix soon (down, rate limited, timed out or
+// unusable output). A request shed by the admission limiter is not one of
+// them: it reaches the client as ResourceExhausted, which is the signal to
+// back off or scale out. Fallback results are marked by their Source and not
+// cached.
+func (s *AIService) orFallback(ctx context.Context, text string, categories []string, tr *domain.Transaction, err error) (*domain.Transaction, error) {
+	if err == nil || s.fallback == nil || ctx.Err() != nil || errors.Is(err, domain.ErrOverloaded) {
+		return tr, err
+	}
+	switch {
+	case errors.Is(err, domain.ErrUnavailable),
+		errors.Is(err, domain.ErrRateLimited),
+		errors.Is(err, domain.ErrInvalidOutput),
+		errors.Is(err, context.DeadlineExceeded):
+	default:
+		return tr, err
+	}
+
+	log.Printf("LLM failed (%v), using the fallback parser", err)
+	fbTr, fbErr := s.fallback.ParseOcrResponseToJson(ctx, text, categories)
+	if fbErr != nil {
+		fallbacks.Add("failed", 1)
+		log.Printf("fallback parser failed: %v", fbErr)
+		return nil, err // the LLM error is the one worth reporting
+	}
+	fallbacks.Add("used", 1)
+	return fbTr, nil
+}
+
+// reportSource tells the caller which parser produced tr in the
+// "x-transaction-source" response header.
+func reportSource(ctx context.Context, tr *domain.Transaction) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-transaction-source", sourceOf(tr))); err != nil {
+		log.Printf("failed to set transaction source header: %v", err)
+	}
+}
+
+func sourceOf(tr *domain.Transaction) string {
+	if tr.Source == "" {
+		return domain.SourceLLM
+	}
+	return tr.Source
+}
+
+// generate runs one LLM call, waiting for a slot first when a limiter is
+// configured.
+func (s *AIService) generate(ctx context.Context, call func() (*domain.Transaction, error)) (*domain.Transaction, error) {
+	if s.llmLimiter != nil {
+		release, err := s.llmLimiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+	progress.Report(ctx, progress.Event{Stage: progress.LLMGenerating})
+	return call()
+}
+
+// settleDate makes tr.Date the date printed in text when the model gave
+// none or a different day or time, converts a Buddhist Era year the model passed
+// through, and puts times without an offset in the default timezone. Typed
+// text (see withReference) may name a relative day instead, and is dated at
+// the reference time when it names none.
+func (s *AIService) settleDate(ctx context.Context, text string, tr *domain.Transaction) {
+	now, typed := reference(ctx)
+	if !typed {
+		now = time.Now().In(s.loc)
+	}
+	printed, found := thaidate.Find(text, now)
+	if !found && typed {
+		printed, found = thaidate.FindRelative(text, now)
+	}
+	model, beFixed, ok := thaidate.ParseISO(tr.Date, now.Location())
+	switch {
+	case found && !ok:
+		dateFixes.Add("filled", 1)
+		tr.Date = printed.String()
+	case found && !model.SameDay(printed):
+		dateFixes.Add("overridden", 1)
+		log.Printf("model date %q replaced by %s from the text", tr.Date, printed)
+		tr.Date = printed.String()
+	case found && printed.HasTime && !(model.HasTime && model.Time.Equal(printed.Time)):
+		if model.HasTime {
+			dateFixes.Add("time_overridden", 1)
+		}
+		tr.Date = printed.String()
+	case typed && !found:
+		// the text names no day, so a date from the model is made up
+		if tr.Date != "" {
+			dateFixes.Add("defaulted", 1)
+		}
+		tr.Date = thaidate.Date{Time: now, HasTime: true}.String()
+	case ok:
+		if beFixed {
+			dateFixes.Add("be_year", 1)
+		}
+		tr.Date = model.String()
+	case tr.Date != "":
+		dateFixes.Add("dropped", 1) // not a date we can read, and none printed
+		tr.Date = ""
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/domain"
+	"github.com/cp25sy5-modjot/ai-wrapper-service/internal/pkg/admission"
+	aiwpb "github.com/cp25sy5-modjot/proto/gen/ai/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLLM answers every text with tr, or fails with err.
+type fakeLLM struct {
+	tr    *domain.Transaction
+	err   error
+	calls int
+}
+
+func (f *fakeLLM) ParseOcrResponseToJson(ctx context.Context, text string, categories []string) (*domain.Transaction, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tr.Clone(), nil
+}
+
+func TestShedRequestSkipsFallback(t *testing.T) {
+	limiter := admission.New("usecase_test_llm", 1, 0, time.Minute)
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	llm := &fakeLLM{tr: &domain.Transaction{Title: "llm"}}
+	fallback := &fakeLLM{tr: &domain.Transaction{Title: "rules", Source: domain.SourceRules}}
+	svc := NewAIService(nil, llm, WithLLMLimiter(limiter), WithFallback(fallback))
+
+	_, err = svc.BuildTransactionFromText(context.Background(), &aiwpb.BuildTransactionFromTextRequest{TextToAnalyze: "coffee 60"})
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("code = %v (%v), want ResourceExhausted", got, err)
+	}
+	if llm.calls != 0 || fallback.calls != 0 {
+		t.Errorf("llm called %d times, fallback %d times; want neither", llm.calls, fallback.calls)
+	}
+}
+
+func TestUnavailableLLMFallsBack(t *testing.T) {
+	llm := &fakeLLM{err: domain.ErrUnavailable}
+	fallback := &fakeLLM{tr: &domain.Transaction{Title: "rules", Source: domain.SourceRules}}
+	svc := NewAIService(nil, llm, WithFallback(fallback))
+
+	resp, err := svc.BuildTransactionFromText(context.Background(), &aiwpb.BuildTransactionFromTextRequest{TextToAnalyze: "coffee 60"})
+	if err != nil || resp.GetTitle() != "rules" {
+		t.Errorf("got %v, %v; want the fallback's answer", resp, err)
+	}
+}
+
+func TestV2TextDates(t *testing.T) {
+	now := time.Now().In(bangkok)
+	tests := []struct {
+		name  string
+		text  string
+		model string
+		want  string // "now" for the request time
+	}{
+		{"no day named: request time, not the model's date", "coffee 60", "2020-01-01", "now"},
+		{"no day named, no model date", "coffee 60", "", "now"},
+		{"relative day", "ข้าวมันไก่ 50 บาท เมื่อวาน", "2020-01-01", now.AddDate(0, 0, -1).Format("2006-01-02")},
+		{"printed date", "coffee 60 15/03/2567", "2024-03-16", "2024-03-15"},
+	}
+	for _, tt := range tests {
+		llm := &fakeLLM{tr: &domain.Transaction{Title: "t", Date: tt.model}}
+		svc := NewAIService(nil, llm)
+		resp, err := svc.BuildTransactionFromText(context.Background(), &aiwpb.BuildTransactionFromTextRequest{TextToAnalyze: tt.text})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.want != "now" {
+			if resp.GetDate() != tt.want {
+				t.Errorf("%s: date = %q, want %q", tt.name, resp.GetDate(), tt.want)
+			}
+			continue
+		}
+		got, err := time.Parse(time.RFC3339, resp.GetDate())
+		if err != nil || got.Sub(now).Abs() > time.Minute {
+			t.Errorf("%s: date = %q, want the request time %s", tt.name, resp.GetDate(), now.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestReceiptKeepsModelDate(t *testing.T) {
+	svc := NewAIService(nil, nil)
+	tr := &domain.Transaction{Date: "2024-03-16"}
+	svc.settleDate(context.Background(), "7-ELEVEN\nCOKE 35.00", tr)
+	if tr.Date != "2024-03-16" {
+		t.Errorf("receipt text naming no day: date = %q, want the model's 2024-03-16", tr.Date)
+	}
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type referenceKey struct{}
+
+// withReference marks ctx as a request with typed rather than printed text:
+// relative days in it are resolved against ts in tz, and it is dated ts when
+// it names no day. ts defaults to now and tz to the service timezone.
+func (s *AIService) withReference(ctx context.Context, ts *timestamppb.Timestamp, tz string) (context.Context, error) {
+	loc := s.loc
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, invalidArg("timezone", fmt.Sprintf("unknown timezone %q", tz))
+		}
+		loc = l
+	}
+	now := time.Now()
+	if ts != nil {
+		if err := ts.CheckValid(); err != nil {
+			return nil, invalidArg("reference_time", err.Error())
+		}
+		now = ts.AsTime()
+	}
+	return context.WithValue(ctx, referenceKey{}, now.In(loc)), nil
+}
+
+// reference returns the time set by withReference; ok is false for receipt
+// text.
+func reference(ctx context.Context) (now time.Time, ok bool) {
+	now, ok = ctx.Value(referenceKey{}).(time.Time)
+	return now, ok
+}
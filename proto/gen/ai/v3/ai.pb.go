@@ -0,0 +1,1781 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.28.0
+// source: ai/v3/ai.proto
+
+package aiwpbv3
+
+import (
+	v2 "github.com/cp25sy5-modjot/proto/gen/ai/v2"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PaymentMethod int32
+
+const (
+	PaymentMethod_PAYMENT_METHOD_UNSPECIFIED PaymentMethod = 0
+	PaymentMethod_PAYMENT_METHOD_CASH        PaymentMethod = 1
+	PaymentMethod_PAYMENT_METHOD_CARD        PaymentMethod = 2
+	PaymentMethod_PAYMENT_METHOD_QR          PaymentMethod = 3
+	PaymentMethod_PAYMENT_METHOD_E_WALLET    PaymentMethod = 4
+)
+
+// Enum value maps for PaymentMethod.
+var (
+	PaymentMethod_name = map[int32]string{
+		0: "PAYMENT_METHOD_UNSPECIFIED",
+		1: "PAYMENT_METHOD_CASH",
+		2: "PAYMENT_METHOD_CARD",
+		3: "PAYMENT_METHOD_QR",
+		4: "PAYMENT_METHOD_E_WALLET",
+	}
+	PaymentMethod_value = map[string]int32{
+		"PAYMENT_METHOD_UNSPECIFIED": 0,
+		"PAYMENT_METHOD_CASH":        1,
+		"PAYMENT_METHOD_CARD":        2,
+		"PAYMENT_METHOD_QR":          3,
+		"PAYMENT_METHOD_E_WALLET":    4,
+	}
+)
+
+func (x PaymentMethod) Enum() *PaymentMethod {
+	p := new(PaymentMethod)
+	*p = x
+	return p
+}
+
+func (x PaymentMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_ai_v3_ai_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentMethod) Type() protoreflect.EnumType {
+	return &file_ai_v3_ai_proto_enumTypes[0]
+}
+
+func (x PaymentMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentMethod.Descriptor instead.
+func (PaymentMethod) EnumDescriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{0}
+}
+
+type ProgressEvent_Stage int32
+
+const (
+	ProgressEvent_STAGE_UNSPECIFIED    ProgressEvent_Stage = 0
+	ProgressEvent_STAGE_RECEIVED       ProgressEvent_Stage = 1
+	ProgressEvent_STAGE_PREPROCESSED   ProgressEvent_Stage = 2
+	ProgressEvent_STAGE_OCR_STARTED    ProgressEvent_Stage = 3
+	ProgressEvent_STAGE_OCR_RETRYING   ProgressEvent_Stage = 4
+	ProgressEvent_STAGE_OCR_FINISHED   ProgressEvent_Stage = 5
+	ProgressEvent_STAGE_LLM_GENERATING ProgressEvent_Stage = 6
+	ProgressEvent_STAGE_COMPLETED      ProgressEvent_Stage = 7
+	ProgressEvent_STAGE_FAILED         ProgressEvent_Stage = 8
+)
+
+// Enum value maps for ProgressEvent_Stage.
+var (
+	ProgressEvent_Stage_name = map[int32]string{
+		0: "STAGE_UNSPECIFIED",
+		1: "STAGE_RECEIVED",
+		2: "STAGE_PREPROCESSED",
+		3: "STAGE_OCR_STARTED",
+		4: "STAGE_OCR_RETRYING",
+		5: "STAGE_OCR_FINISHED",
+		6: "STAGE_LLM_GENERATING",
+		7: "STAGE_COMPLETED",
+		8: "STAGE_FAILED",
+	}
+	ProgressEvent_Stage_value = map[string]int32{
+		"STAGE_UNSPECIFIED":    0,
+		"STAGE_RECEIVED":       1,
+		"STAGE_PREPROCESSED":   2,
+		"STAGE_OCR_STARTED":    3,
+		"STAGE_OCR_RETRYING":   4,
+		"STAGE_OCR_FINISHED":   5,
+		"STAGE_LLM_GENERATING": 6,
+		"STAGE_COMPLETED":      7,
+		"STAGE_FAILED":         8,
+	}
+)
+
+func (x ProgressEvent_Stage) Enum() *ProgressEvent_Stage {
+	p := new(ProgressEvent_Stage)
+	*p = x
+	return p
+}
+
+func (x ProgressEvent_Stage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProgressEvent_Stage) Descriptor() protoreflect.EnumDescriptor {
+	return file_ai_v3_ai_proto_enumTypes[1].Descriptor()
+}
+
+func (ProgressEvent_Stage) Type() protoreflect.EnumType {
+	return &file_ai_v3_ai_proto_enumTypes[1]
+}
+
+func (x ProgressEvent_Stage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProgressEvent_Stage.Descriptor instead.
+func (ProgressEvent_Stage) EnumDescriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type TransactionItemV3 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title          string  `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Price          float64 `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Category       string  `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Quantity       float64 `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice      float64 `protobuf:"fixed64,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Unit           string  `protobuf:"bytes,6,opt,name=unit,proto3" json:"unit,omitempty"`
+	PriceMoney     *Money  `protobuf:"bytes,7,opt,name=price_money,json=priceMoney,proto3" json:"price_money,omitempty"`
+	UnitPriceMoney *Money  `protobuf:"bytes,8,opt,name=unit_price_money,json=unitPriceMoney,proto3" json:"unit_price_money,omitempty"`
+}
+
+func (x *TransactionItemV3) Reset() {
+	*x = TransactionItemV3{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionItemV3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionItemV3) ProtoMessage() {}
+
+func (x *TransactionItemV3) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionItemV3.ProtoReflect.Descriptor instead.
+func (*TransactionItemV3) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TransactionItemV3) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TransactionItemV3) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *TransactionItemV3) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *TransactionItemV3) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *TransactionItemV3) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *TransactionItemV3) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *TransactionItemV3) GetPriceMoney() *Money {
+	if x != nil {
+		return x.PriceMoney
+	}
+	return nil
+}
+
+func (x *TransactionItemV3) GetUnitPriceMoney() *Money {
+	if x != nil {
+		return x.UnitPriceMoney
+	}
+	return nil
+}
+
+type Money struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Currency   string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	MinorUnits int64  `protobuf:"varint,2,opt,name=minor_units,json=minorUnits,proto3" json:"minor_units,omitempty"`
+	Exponent   int32  `protobuf:"varint,3,opt,name=exponent,proto3" json:"exponent,omitempty"`
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Money) GetMinorUnits() int64 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *Money) GetExponent() int32 {
+	if x != nil {
+		return x.Exponent
+	}
+	return 0
+}
+
+type TransactionResponseV3 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title              string               `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Date               string               `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Items              []*TransactionItemV3 `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Subtotal           *float64             `protobuf:"fixed64,4,opt,name=subtotal,proto3,oneof" json:"subtotal,omitempty"`
+	DiscountTotal      *float64             `protobuf:"fixed64,5,opt,name=discount_total,json=discountTotal,proto3,oneof" json:"discount_total,omitempty"`
+	ServiceCharge      *float64             `protobuf:"fixed64,6,opt,name=service_charge,json=serviceCharge,proto3,oneof" json:"service_charge,omitempty"`
+	VatAmount          *float64             `protobuf:"fixed64,7,opt,name=vat_amount,json=vatAmount,proto3,oneof" json:"vat_amount,omitempty"`
+	VatRate            *float64             `protobuf:"fixed64,8,opt,name=vat_rate,json=vatRate,proto3,oneof" json:"vat_rate,omitempty"`
+	GrandTotal         *float64             `protobuf:"fixed64,9,opt,name=grand_total,json=grandTotal,proto3,oneof" json:"grand_total,omitempty"`
+	PaymentMethod      PaymentMethod        `protobuf:"varint,10,opt,name=payment_method,json=paymentMethod,proto3,enum=ai.v3.PaymentMethod" json:"payment_method,omitempty"`
+	MerchantTaxId      string               `protobuf:"bytes,11,opt,name=merchant_tax_id,json=merchantTaxId,proto3" json:"merchant_tax_id,omitempty"`
+	ReceiptNumber      string               `protobuf:"bytes,12,opt,name=receipt_number,json=receiptNumber,proto3" json:"receipt_number,omitempty"`
+	Source             string               `protobuf:"bytes,13,opt,name=source,proto3" json:"source,omitempty"`
+	Reconciliation     *Reconciliation      `protobuf:"bytes,14,opt,name=reconciliation,proto3" json:"reconciliation,omitempty"`
+	Currency           string               `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	SubtotalMoney      *Money               `protobuf:"bytes,16,opt,name=subtotal_money,json=subtotalMoney,proto3" json:"subtotal_money,omitempty"`
+	DiscountTotalMoney *Money               `protobuf:"bytes,17,opt,name=discount_total_money,json=discountTotalMoney,proto3" json:"discount_total_money,omitempty"`
+	ServiceChargeMoney *Money               `protobuf:"bytes,18,opt,name=service_charge_money,json=serviceChargeMoney,proto3" json:"service_charge_money,omitempty"`
+	VatAmountMoney     *Money               `protobuf:"bytes,19,opt,name=vat_amount_money,json=vatAmountMoney,proto3" json:"vat_amount_money,omitempty"`
+	GrandTotalMoney    *Money               `protobuf:"bytes,20,opt,name=grand_total_money,json=grandTotalMoney,proto3" json:"grand_total_money,omitempty"`
+}
+
+func (x *TransactionResponseV3) Reset() {
+	*x = TransactionResponseV3{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionResponseV3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionResponseV3) ProtoMessage() {}
+
+func (x *TransactionResponseV3) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionResponseV3.ProtoReflect.Descriptor instead.
+func (*TransactionResponseV3) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TransactionResponseV3) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetItems() []*TransactionItemV3 {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetSubtotal() float64 {
+	if x != nil && x.Subtotal != nil {
+		return *x.Subtotal
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetDiscountTotal() float64 {
+	if x != nil && x.DiscountTotal != nil {
+		return *x.DiscountTotal
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetServiceCharge() float64 {
+	if x != nil && x.ServiceCharge != nil {
+		return *x.ServiceCharge
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetVatAmount() float64 {
+	if x != nil && x.VatAmount != nil {
+		return *x.VatAmount
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetVatRate() float64 {
+	if x != nil && x.VatRate != nil {
+		return *x.VatRate
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetGrandTotal() float64 {
+	if x != nil && x.GrandTotal != nil {
+		return *x.GrandTotal
+	}
+	return 0
+}
+
+func (x *TransactionResponseV3) GetPaymentMethod() PaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return PaymentMethod_PAYMENT_METHOD_UNSPECIFIED
+}
+
+func (x *TransactionResponseV3) GetMerchantTaxId() string {
+	if x != nil {
+		return x.MerchantTaxId
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetReceiptNumber() string {
+	if x != nil {
+		return x.ReceiptNumber
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetReconciliation() *Reconciliation {
+	if x != nil {
+		return x.Reconciliation
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransactionResponseV3) GetSubtotalMoney() *Money {
+	if x != nil {
+		return x.SubtotalMoney
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetDiscountTotalMoney() *Money {
+	if x != nil {
+		return x.DiscountTotalMoney
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetServiceChargeMoney() *Money {
+	if x != nil {
+		return x.ServiceChargeMoney
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetVatAmountMoney() *Money {
+	if x != nil {
+		return x.VatAmountMoney
+	}
+	return nil
+}
+
+func (x *TransactionResponseV3) GetGrandTotalMoney() *Money {
+	if x != nil {
+		return x.GrandTotalMoney
+	}
+	return nil
+}
+
+type Reconciliation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Consistent         bool                      `protobuf:"varint,1,opt,name=consistent,proto3" json:"consistent,omitempty"`
+	ItemsTotal         float64                   `protobuf:"fixed64,2,opt,name=items_total,json=itemsTotal,proto3" json:"items_total,omitempty"`
+	ExpectedTotal      float64                   `protobuf:"fixed64,3,opt,name=expected_total,json=expectedTotal,proto3" json:"expected_total,omitempty"`
+	Discrepancy        float64                   `protobuf:"fixed64,4,opt,name=discrepancy,proto3" json:"discrepancy,omitempty"`
+	Suspects           []*Reconciliation_Suspect `protobuf:"bytes,5,rep,name=suspects,proto3" json:"suspects,omitempty"`
+	ItemsTotalMoney    *Money                    `protobuf:"bytes,6,opt,name=items_total_money,json=itemsTotalMoney,proto3" json:"items_total_money,omitempty"`
+	ExpectedTotalMoney *Money                    `protobuf:"bytes,7,opt,name=expected_total_money,json=expectedTotalMoney,proto3" json:"expected_total_money,omitempty"`
+	DiscrepancyMoney   *Money                    `protobuf:"bytes,8,opt,name=discrepancy_money,json=discrepancyMoney,proto3" json:"discrepancy_money,omitempty"`
+}
+
+func (x *Reconciliation) Reset() {
+	*x = Reconciliation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reconciliation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reconciliation) ProtoMessage() {}
+
+func (x *Reconciliation) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reconciliation.ProtoReflect.Descriptor instead.
+func (*Reconciliation) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Reconciliation) GetConsistent() bool {
+	if x != nil {
+		return x.Consistent
+	}
+	return false
+}
+
+func (x *Reconciliation) GetItemsTotal() float64 {
+	if x != nil {
+		return x.ItemsTotal
+	}
+	return 0
+}
+
+func (x *Reconciliation) GetExpectedTotal() float64 {
+	if x != nil {
+		return x.ExpectedTotal
+	}
+	return 0
+}
+
+func (x *Reconciliation) GetDiscrepancy() float64 {
+	if x != nil {
+		return x.Discrepancy
+	}
+	return 0
+}
+
+func (x *Reconciliation) GetSuspects() []*Reconciliation_Suspect {
+	if x != nil {
+		return x.Suspects
+	}
+	return nil
+}
+
+func (x *Reconciliation) GetItemsTotalMoney() *Money {
+	if x != nil {
+		return x.ItemsTotalMoney
+	}
+	return nil
+}
+
+func (x *Reconciliation) GetExpectedTotalMoney() *Money {
+	if x != nil {
+		return x.ExpectedTotalMoney
+	}
+	return nil
+}
+
+func (x *Reconciliation) GetDiscrepancyMoney() *Money {
+	if x != nil {
+		return x.DiscrepancyMoney
+	}
+	return nil
+}
+
+type BuildTransactionFromTextRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TextToAnalyze string                 `protobuf:"bytes,1,opt,name=text_to_analyze,json=textToAnalyze,proto3" json:"text_to_analyze,omitempty"`
+	Categories    []string               `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	ReferenceTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=reference_time,json=referenceTime,proto3" json:"reference_time,omitempty"`
+	Timezone      string                 `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+}
+
+func (x *BuildTransactionFromTextRequest) Reset() {
+	*x = BuildTransactionFromTextRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildTransactionFromTextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildTransactionFromTextRequest) ProtoMessage() {}
+
+func (x *BuildTransactionFromTextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildTransactionFromTextRequest.ProtoReflect.Descriptor instead.
+func (*BuildTransactionFromTextRequest) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BuildTransactionFromTextRequest) GetTextToAnalyze() string {
+	if x != nil {
+		return x.TextToAnalyze
+	}
+	return ""
+}
+
+func (x *BuildTransactionFromTextRequest) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *BuildTransactionFromTextRequest) GetReferenceTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReferenceTime
+	}
+	return nil
+}
+
+func (x *BuildTransactionFromTextRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type BuildTransactionsFromTextResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transactions []*TransactionResponseV3 `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+}
+
+func (x *BuildTransactionsFromTextResponse) Reset() {
+	*x = BuildTransactionsFromTextResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildTransactionsFromTextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildTransactionsFromTextResponse) ProtoMessage() {}
+
+func (x *BuildTransactionsFromTextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildTransactionsFromTextResponse.ProtoReflect.Descriptor instead.
+func (*BuildTransactionsFromTextResponse) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BuildTransactionsFromTextResponse) GetTransactions() []*TransactionResponseV3 {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type UploadMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Categories  []string `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	Filename    string   `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType string   `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	TotalSize   int64    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+}
+
+func (x *UploadMetadata) Reset() {
+	*x = UploadMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMetadata) ProtoMessage() {}
+
+func (x *UploadMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMetadata.ProtoReflect.Descriptor instead.
+func (*UploadMetadata) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UploadMetadata) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *UploadMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type UploadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Payload:
+	//	*UploadRequest_Metadata
+	//	*UploadRequest_Chunk
+	Payload isUploadRequest_Payload `protobuf_oneof:"payload"`
+}
+
+func (x *UploadRequest) Reset() {
+	*x = UploadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadRequest) ProtoMessage() {}
+
+func (x *UploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadRequest.ProtoReflect.Descriptor instead.
+func (*UploadRequest) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{7}
+}
+
+func (m *UploadRequest) GetPayload() isUploadRequest_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *UploadRequest) GetMetadata() *UploadMetadata {
+	if x, ok := x.GetPayload().(*UploadRequest_Metadata); ok {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UploadRequest) GetChunk() []byte {
+	if x, ok := x.GetPayload().(*UploadRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isUploadRequest_Payload interface {
+	isUploadRequest_Payload()
+}
+
+type UploadRequest_Metadata struct {
+	Metadata *UploadMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadRequest_Metadata) isUploadRequest_Payload() {}
+
+func (*UploadRequest_Chunk) isUploadRequest_Payload() {}
+
+type ProgressEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stage          ProgressEvent_Stage    `protobuf:"varint,1,opt,name=stage,proto3,enum=ai.v3.ProgressEvent_Stage" json:"stage,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Attempt        int32                  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	RetryInMs      int64                  `protobuf:"varint,4,opt,name=retry_in_ms,json=retryInMs,proto3" json:"retry_in_ms,omitempty"`
+	ExtractedText  string                 `protobuf:"bytes,5,opt,name=extracted_text,json=extractedText,proto3" json:"extracted_text,omitempty"`
+	Transaction    *TransactionResponseV3 `protobuf:"bytes,6,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	ErrorCode      int32                  `protobuf:"varint,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Source         string                 `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`
+	OcrTotalPages  int32                  `protobuf:"varint,9,opt,name=ocr_total_pages,json=ocrTotalPages,proto3" json:"ocr_total_pages,omitempty"`
+	OcrFailedPages []int32                `protobuf:"varint,10,rep,packed,name=ocr_failed_pages,json=ocrFailedPages,proto3" json:"ocr_failed_pages,omitempty"`
+}
+
+func (x *ProgressEvent) Reset() {
+	*x = ProgressEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProgressEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgressEvent) ProtoMessage() {}
+
+func (x *ProgressEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgressEvent.ProtoReflect.Descriptor instead.
+func (*ProgressEvent) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProgressEvent) GetStage() ProgressEvent_Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return ProgressEvent_STAGE_UNSPECIFIED
+}
+
+func (x *ProgressEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ProgressEvent) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetRetryInMs() int64 {
+	if x != nil {
+		return x.RetryInMs
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetExtractedText() string {
+	if x != nil {
+		return x.ExtractedText
+	}
+	return ""
+}
+
+func (x *ProgressEvent) GetTransaction() *TransactionResponseV3 {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *ProgressEvent) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ProgressEvent) GetOcrTotalPages() int32 {
+	if x != nil {
+		return x.OcrTotalPages
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetOcrFailedPages() []int32 {
+	if x != nil {
+		return x.OcrFailedPages
+	}
+	return nil
+}
+
+type StreamTransactionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Input:
+	//	*StreamTransactionRequest_ImageData
+	//	*StreamTransactionRequest_TextToAnalyze
+	Input         isStreamTransactionRequest_Input `protobuf_oneof:"input"`
+	Categories    []string                         `protobuf:"bytes,3,rep,name=categories,proto3" json:"categories,omitempty"`
+	ReferenceTime *timestamppb.Timestamp           `protobuf:"bytes,4,opt,name=reference_time,json=referenceTime,proto3" json:"reference_time,omitempty"`
+	Timezone      string                           `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+}
+
+func (x *StreamTransactionRequest) Reset() {
+	*x = StreamTransactionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionRequest) ProtoMessage() {}
+
+func (x *StreamTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionRequest.ProtoReflect.Descriptor instead.
+func (*StreamTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{9}
+}
+
+func (m *StreamTransactionRequest) GetInput() isStreamTransactionRequest_Input {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (x *StreamTransactionRequest) GetImageData() []byte {
+	if x, ok := x.GetInput().(*StreamTransactionRequest_ImageData); ok {
+		return x.ImageData
+	}
+	return nil
+}
+
+func (x *StreamTransactionRequest) GetTextToAnalyze() string {
+	if x, ok := x.GetInput().(*StreamTransactionRequest_TextToAnalyze); ok {
+		return x.TextToAnalyze
+	}
+	return ""
+}
+
+func (x *StreamTransactionRequest) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *StreamTransactionRequest) GetReferenceTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReferenceTime
+	}
+	return nil
+}
+
+func (x *StreamTransactionRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type isStreamTransactionRequest_Input interface {
+	isStreamTransactionRequest_Input()
+}
+
+type StreamTransactionRequest_ImageData struct {
+	ImageData []byte `protobuf:"bytes,1,opt,name=image_data,json=imageData,proto3,oneof"`
+}
+
+type StreamTransactionRequest_TextToAnalyze struct {
+	TextToAnalyze string `protobuf:"bytes,2,opt,name=text_to_analyze,json=textToAnalyze,proto3,oneof"`
+}
+
+func (*StreamTransactionRequest_ImageData) isStreamTransactionRequest_Input() {}
+
+func (*StreamTransactionRequest_TextToAnalyze) isStreamTransactionRequest_Input() {}
+
+type TransactionStreamEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*TransactionStreamEvent_Item
+	//	*TransactionStreamEvent_Transaction
+	Event  isTransactionStreamEvent_Event `protobuf_oneof:"event"`
+	Source string                         `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+}
+
+func (x *TransactionStreamEvent) Reset() {
+	*x = TransactionStreamEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionStreamEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionStreamEvent) ProtoMessage() {}
+
+func (x *TransactionStreamEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionStreamEvent.ProtoReflect.Descriptor instead.
+func (*TransactionStreamEvent) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{10}
+}
+
+func (m *TransactionStreamEvent) GetEvent() isTransactionStreamEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *TransactionStreamEvent) GetItem() *TransactionItemV3 {
+	if x, ok := x.GetEvent().(*TransactionStreamEvent_Item); ok {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *TransactionStreamEvent) GetTransaction() *TransactionResponseV3 {
+	if x, ok := x.GetEvent().(*TransactionStreamEvent_Transaction); ok {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *TransactionStreamEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type isTransactionStreamEvent_Event interface {
+	isTransactionStreamEvent_Event()
+}
+
+type TransactionStreamEvent_Item struct {
+	Item *TransactionItemV3 `protobuf:"bytes,1,opt,name=item,proto3,oneof"`
+}
+
+type TransactionStreamEvent_Transaction struct {
+	Transaction *TransactionResponseV3 `protobuf:"bytes,2,opt,name=transaction,proto3,oneof"`
+}
+
+func (*TransactionStreamEvent_Item) isTransactionStreamEvent_Event() {}
+
+func (*TransactionStreamEvent_Transaction) isTransactionStreamEvent_Event() {}
+
+type Reconciliation_Suspect struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index  int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *Reconciliation_Suspect) Reset() {
+	*x = Reconciliation_Suspect{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ai_v3_ai_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reconciliation_Suspect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reconciliation_Suspect) ProtoMessage() {}
+
+func (x *Reconciliation_Suspect) ProtoReflect() protoreflect.Message {
+	mi := &file_ai_v3_ai_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reconciliation_Suspect.ProtoReflect.Descriptor instead.
+func (*Reconciliation_Suspect) Descriptor() ([]byte, []int) {
+	return file_ai_v3_ai_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *Reconciliation_Suspect) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Reconciliation_Suspect) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_ai_v3_ai_proto protoreflect.FileDescriptor
+
+var file_ai_v3_ai_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x61, 0x69, 0x2f, 0x76, 0x33, 0x2f, 0x61, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x05, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x1a, 0x0e, 0x61, 0x69, 0x2f, 0x76, 0x32, 0x2f, 0x61,
+	0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x91, 0x02, 0x0a, 0x11, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x33, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x75, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x2d, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x6d,
+	0x6f, 0x6e, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e,
+	0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x63, 0x65, 0x4d,
+	0x6f, 0x6e, 0x65, 0x79, 0x12, 0x36, 0x0a, 0x10, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x5f, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x0e, 0x75, 0x6e,
+	0x69, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x22, 0x60, 0x0a, 0x05,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x55, 0x6e, 0x69,
+	0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x22, 0xd9,
+	0x07, 0x0a, 0x15, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x33, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x08, 0x73, 0x75, 0x62, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x08, 0x73, 0x75, 0x62, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x0d, 0x64,
+	0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12,
+	0x2a, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x76,
+	0x61, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x48,
+	0x03, 0x52, 0x09, 0x76, 0x61, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x1e, 0x0a, 0x08, 0x76, 0x61, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x04, 0x52, 0x07, 0x76, 0x61, 0x74, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x24, 0x0a, 0x0b, 0x67, 0x72, 0x61, 0x6e, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x05, 0x52, 0x0a, 0x67, 0x72, 0x61, 0x6e, 0x64, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e,
+	0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x5f, 0x74,
+	0x61, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x65, 0x72,
+	0x63, 0x68, 0x61, 0x6e, 0x74, 0x54, 0x61, 0x78, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65,
+	0x63, 0x65, 0x69, 0x70, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x0e, 0x72, 0x65, 0x63,
+	0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63,
+	0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63,
+	0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x33, 0x0a, 0x0e, 0x73, 0x75, 0x62, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61,
+	0x69, 0x2e, 0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x0d, 0x73, 0x75, 0x62, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x3e, 0x0a, 0x14, 0x64, 0x69, 0x73,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d, 0x6f, 0x6e, 0x65,
+	0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x12, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x3e, 0x0a, 0x14, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x5f, 0x6d, 0x6f, 0x6e, 0x65,
+	0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x12, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x68,
+	0x61, 0x72, 0x67, 0x65, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x36, 0x0a, 0x10, 0x76, 0x61, 0x74,
+	0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65,
+	0x79, 0x52, 0x0e, 0x76, 0x61, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x6f, 0x6e, 0x65,
+	0x79, 0x12, 0x38, 0x0a, 0x11, 0x67, 0x72, 0x61, 0x6e, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61,
+	0x69, 0x2e, 0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x0f, 0x67, 0x72, 0x61, 0x6e,
+	0x64, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x73, 0x75, 0x62, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x69, 0x73,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x11, 0x0a, 0x0f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x76, 0x61, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x76, 0x61, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x67,
+	0x72, 0x61, 0x6e, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0xc3, 0x03, 0x0a, 0x0e, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x25,
+	0x0a, 0x0e, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70,
+	0x61, 0x6e, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63,
+	0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x12, 0x39, 0x0a, 0x08, 0x73, 0x75, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x69, 0x2e, 0x76,
+	0x33, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x53, 0x75, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x08, 0x73, 0x75, 0x73, 0x70, 0x65, 0x63,
+	0x74, 0x73, 0x12, 0x38, 0x0a, 0x11, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x0f, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x3e, 0x0a, 0x14,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d,
+	0x6f, 0x6e, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e,
+	0x76, 0x33, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x12, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x12, 0x39, 0x0a, 0x11,
+	0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x6f, 0x6e, 0x65,
+	0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x10, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e,
+	0x63, 0x79, 0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x1a, 0x37, 0x0a, 0x07, 0x53, 0x75, 0x73, 0x70, 0x65,
+	0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x22, 0xc8, 0x01, 0x0a, 0x1f, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x74, 0x6f, 0x5f,
+	0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74,
+	0x65, 0x78, 0x74, 0x54, 0x6f, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0e,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0d, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x22, 0x65, 0x0a, 0x21, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x40, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x56, 0x33, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53,
+	0x69, 0x7a, 0x65, 0x22, 0x67, 0x0a, 0x0d, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0xda, 0x04, 0x0a,
+	0x0d, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x30,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x12, 0x1e, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x69, 0x6e,
+	0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x74, 0x72, 0x79,
+	0x49, 0x6e, 0x4d, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x65,
+	0x64, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x65, 0x64, 0x54, 0x65, 0x78, 0x74, 0x12, 0x3e, 0x0a, 0x0b, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x52, 0x0b,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x6f, 0x63, 0x72, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6f, 0x63, 0x72,
+	0x54, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x6f, 0x63,
+	0x72, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x0e, 0x6f, 0x63, 0x72, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x50,
+	0x61, 0x67, 0x65, 0x73, 0x22, 0xd2, 0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x15,
+	0x0a, 0x11, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x52,
+	0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41,
+	0x47, 0x45, 0x5f, 0x50, 0x52, 0x45, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x53, 0x53, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x4f, 0x43, 0x52, 0x5f, 0x53,
+	0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x47,
+	0x45, 0x5f, 0x4f, 0x43, 0x52, 0x5f, 0x52, 0x45, 0x54, 0x52, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x04,
+	0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x4f, 0x43, 0x52, 0x5f, 0x46, 0x49,
+	0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x54, 0x41, 0x47,
+	0x45, 0x5f, 0x4c, 0x4c, 0x4d, 0x5f, 0x47, 0x45, 0x4e, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4e, 0x47,
+	0x10, 0x06, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x07, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x54, 0x41, 0x47, 0x45,
+	0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x08, 0x22, 0xed, 0x01, 0x0a, 0x18, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x09, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x28, 0x0a, 0x0f, 0x74, 0x65, 0x78, 0x74, 0x5f,
+	0x74, 0x6f, 0x5f, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x0d, 0x74, 0x65, 0x78, 0x74, 0x54, 0x6f, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65,
+	0x73, 0x12, 0x41, 0x0a, 0x0e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65,
+	0x42, 0x07, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x22, 0xab, 0x01, 0x0a, 0x16, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x33, 0x48, 0x00, 0x52, 0x04,
+	0x69, 0x74, 0x65, 0x6d, 0x12, 0x40, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x69, 0x2e, 0x76,
+	0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x48, 0x00, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x07,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2a, 0x95, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x41, 0x59,
+	0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x41, 0x59,
+	0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x43, 0x41, 0x53, 0x48,
+	0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45,
+	0x54, 0x48, 0x4f, 0x44, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x50,
+	0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x51, 0x52,
+	0x10, 0x03, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45,
+	0x54, 0x48, 0x4f, 0x44, 0x5f, 0x45, 0x5f, 0x57, 0x41, 0x4c, 0x4c, 0x45, 0x54, 0x10, 0x04, 0x32,
+	0xdb, 0x04, 0x0a, 0x10, 0x41, 0x69, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x60, 0x0a, 0x18, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78, 0x74,
+	0x12, 0x26, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x12, 0x62, 0x0a, 0x19, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x12, 0x27, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61,
+	0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x12, 0x51, 0x0a, 0x19, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x41, 0x6e, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x56, 0x33, 0x28, 0x01, 0x12, 0x68, 0x0a,
+	0x25, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x57, 0x69, 0x74, 0x68, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x27, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46,
+	0x72, 0x6f, 0x6d, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x14, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x55, 0x0a, 0x11, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x2e, 0x61,
+	0x69, 0x2e, 0x76, 0x33, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x6d,
+	0x0a, 0x19, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78, 0x74, 0x12, 0x26, 0x2e, 0x61, 0x69,
+	0x2e, 0x76, 0x33, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x46, 0x72, 0x6f, 0x6d, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x61, 0x69, 0x2e, 0x76, 0x33, 0x2e, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x46, 0x72, 0x6f,
+	0x6d, 0x54, 0x65, 0x78, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x46, 0x5a,
+	0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x70, 0x32, 0x35,
+	0x73, 0x79, 0x35, 0x2d, 0x6d, 0x6f, 0x64, 0x6a, 0x6f, 0x74, 0x2f, 0x61, 0x69, 0x2d, 0x77, 0x72,
+	0x61, 0x70, 0x70, 0x65, 0x72, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x61, 0x69, 0x2f, 0x76, 0x33, 0x3b, 0x61, 0x69,
+	0x77, 0x70, 0x62, 0x76, 0x33, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ai_v3_ai_proto_rawDescOnce sync.Once
+	file_ai_v3_ai_proto_rawDescData = file_ai_v3_ai_proto_rawDesc
+)
+
+func file_ai_v3_ai_proto_rawDescGZIP() []byte {
+	file_ai_v3_ai_proto_rawDescOnce.Do(func() {
+		file_ai_v3_ai_proto_rawDescData = protoimpl.X.CompressGZIP(file_ai_v3_ai_proto_rawDescData)
+	})
+	return file_ai_v3_ai_proto_rawDescData
+}
+
+var file_ai_v3_ai_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_ai_v3_ai_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_ai_v3_ai_proto_goTypes = []any{
+	(PaymentMethod)(0),                          // 0: ai.v3.PaymentMethod
+	(ProgressEvent_Stage)(0),                    // 1: ai.v3.ProgressEvent.Stage
+	(*TransactionItemV3)(nil),                   // 2: ai.v3.TransactionItemV3
+	(*Money)(nil),                               // 3: ai.v3.Money
+	(*TransactionResponseV3)(nil),               // 4: ai.v3.TransactionResponseV3
+	(*Reconciliation)(nil),                      // 5: ai.v3.Reconciliation
+	(*BuildTransactionFromTextRequest)(nil),     // 6: ai.v3.BuildTransactionFromTextRequest
+	(*BuildTransactionsFromTextResponse)(nil),   // 7: ai.v3.BuildTransactionsFromTextResponse
+	(*UploadMetadata)(nil),                      // 8: ai.v3.UploadMetadata
+	(*UploadRequest)(nil),                       // 9: ai.v3.UploadRequest
+	(*ProgressEvent)(nil),                       // 10: ai.v3.ProgressEvent
+	(*StreamTransactionRequest)(nil),            // 11: ai.v3.StreamTransactionRequest
+	(*TransactionStreamEvent)(nil),              // 12: ai.v3.TransactionStreamEvent
+	(*Reconciliation_Suspect)(nil),              // 13: ai.v3.Reconciliation.Suspect
+	(*timestamppb.Timestamp)(nil),               // 14: google.protobuf.Timestamp
+	(*v2.BuildTransactionFromImageRequest)(nil), // 15: ai.v1.BuildTransactionFromImageRequest
+}
+var file_ai_v3_ai_proto_depIdxs = []int32{
+	3,  // 0: ai.v3.TransactionItemV3.price_money:type_name -> ai.v3.Money
+	3,  // 1: ai.v3.TransactionItemV3.unit_price_money:type_name -> ai.v3.Money
+	2,  // 2: ai.v3.TransactionResponseV3.items:type_name -> ai.v3.TransactionItemV3
+	0,  // 3: ai.v3.TransactionResponseV3.payment_method:type_name -> ai.v3.PaymentMethod
+	5,  // 4: ai.v3.TransactionResponseV3.reconciliation:type_name -> ai.v3.Reconciliation
+	3,  // 5: ai.v3.TransactionResponseV3.subtotal_money:type_name -> ai.v3.Money
+	3,  // 6: ai.v3.TransactionResponseV3.discount_total_money:type_name -> ai.v3.Money
+	3,  // 7: ai.v3.TransactionResponseV3.service_charge_money:type_name -> ai.v3.Money
+	3,  // 8: ai.v3.TransactionResponseV3.vat_amount_money:type_name -> ai.v3.Money
+	3,  // 9: ai.v3.TransactionResponseV3.grand_total_money:type_name -> ai.v3.Money
+	13, // 10: ai.v3.Reconciliation.suspects:type_name -> ai.v3.Reconciliation.Suspect
+	3,  // 11: ai.v3.Reconciliation.items_total_money:type_name -> ai.v3.Money
+	3,  // 12: ai.v3.Reconciliation.expected_total_money:type_name -> ai.v3.Money
+	3,  // 13: ai.v3.Reconciliation.discrepancy_money:type_name -> ai.v3.Money
+	14, // 14: ai.v3.BuildTransactionFromTextRequest.reference_time:type_name -> google.protobuf.Timestamp
+	4,  // 15: ai.v3.BuildTransactionsFromTextResponse.transactions:type_name -> ai.v3.TransactionResponseV3
+	8,  // 16: ai.v3.UploadRequest.metadata:type_name -> ai.v3.UploadMetadata
+	1,  // 17: ai.v3.ProgressEvent.stage:type_name -> ai.v3.ProgressEvent.Stage
+	4,  // 18: ai.v3.ProgressEvent.transaction:type_name -> ai.v3.TransactionResponseV3
+	14, // 19: ai.v3.StreamTransactionRequest.reference_time:type_name -> google.protobuf.Timestamp
+	2,  // 20: ai.v3.TransactionStreamEvent.item:type_name -> ai.v3.TransactionItemV3
+	4,  // 21: ai.v3.TransactionStreamEvent.transaction:type_name -> ai.v3.TransactionResponseV3
+	6,  // 22: ai.v3.AiWrapperService.BuildTransactionFromText:input_type -> ai.v3.BuildTransactionFromTextRequest
+	15, // 23: ai.v3.AiWrapperService.BuildTransactionFromImage:input_type -> ai.v1.BuildTransactionFromImageRequest
+	9,  // 24: ai.v3.AiWrapperService.UploadAndBuildTransaction:input_type -> ai.v3.UploadRequest
+	15, // 25: ai.v3.AiWrapperService.BuildTransactionFromImageWithProgress:input_type -> ai.v1.BuildTransactionFromImageRequest
+	11, // 26: ai.v3.AiWrapperService.StreamTransaction:input_type -> ai.v3.StreamTransactionRequest
+	6,  // 27: ai.v3.AiWrapperService.BuildTransactionsFromText:input_type -> ai.v3.BuildTransactionFromTextRequest
+	4,  // 28: ai.v3.AiWrapperService.BuildTransactionFromText:output_type -> ai.v3.TransactionResponseV3
+	4,  // 29: ai.v3.AiWrapperService.BuildTransactionFromImage:output_type -> ai.v3.TransactionResponseV3
+	4,  // 30: ai.v3.AiWrapperService.UploadAndBuildTransaction:output_type -> ai.v3.TransactionResponseV3
+	10, // 31: ai.v3.AiWrapperService.BuildTransactionFromImageWithProgress:output_type -> ai.v3.ProgressEvent
+	12, // 32: ai.v3.AiWrapperService.StreamTransaction:output_type -> ai.v3.TransactionStreamEvent
+	7,  // 33: ai.v3.AiWrapperService.BuildTransactionsFromText:output_type -> ai.v3.BuildTransactionsFromTextResponse
+	28, // [28:34] is the sub-list for method output_type
+	22, // [22:28] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_ai_v3_ai_proto_init() }
+func file_ai_v3_ai_proto_init() {
+	if File_ai_v3_ai_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ai_v3_ai_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*TransactionItemV3); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Money); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*TransactionResponseV3); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Reconciliation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*BuildTransactionFromTextRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*BuildTransactionsFromTextResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ProgressEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamTransactionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*TransactionStreamEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ai_v3_ai_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Reconciliation_Suspect); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_ai_v3_ai_proto_msgTypes[2].OneofWrappers = []any{}
+	file_ai_v3_ai_proto_msgTypes[7].OneofWrappers = []any{
+		(*UploadRequest_Metadata)(nil),
+		(*UploadRequest_Chunk)(nil),
+	}
+	file_ai_v3_ai_proto_msgTypes[9].OneofWrappers = []any{
+		(*StreamTransactionRequest_ImageData)(nil),
+		(*StreamTransactionRequest_TextToAnalyze)(nil),
+	}
+	file_ai_v3_ai_proto_msgTypes[10].OneofWrappers = []any{
+		(*TransactionStreamEvent_Item)(nil),
+		(*TransactionStreamEvent_Transaction)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ai_v3_ai_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ai_v3_ai_proto_goTypes,
+		DependencyIndexes: file_ai_v3_ai_proto_depIdxs,
+		EnumInfos:         file_ai_v3_ai_proto_enumTypes,
+		MessageInfos:      file_ai_v3_ai_proto_msgTypes,
+	}.Build()
+	File_ai_v3_ai_proto = out.File
+	file_ai_v3_ai_proto_rawDesc = nil
+	file_ai_v3_ai_proto_goTypes = nil
+	file_ai_v3_ai_proto_depIdxs = nil
+}
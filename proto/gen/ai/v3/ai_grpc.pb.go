@@ -0,0 +1,312 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.0
+// source: ai/v3/ai.proto
+
+package aiwpbv3
+
+import (
+	context "context"
+	v2 "github.com/cp25sy5-modjot/proto/gen/ai/v2"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AiWrapperService_BuildTransactionFromText_FullMethodName              = "/ai.v3.AiWrapperService/BuildTransactionFromText"
+	AiWrapperService_BuildTransactionFromImage_FullMethodName             = "/ai.v3.AiWrapperService/BuildTransactionFromImage"
+	AiWrapperService_UploadAndBuildTransaction_FullMethodName             = "/ai.v3.AiWrapperService/UploadAndBuildTransaction"
+	AiWrapperService_BuildTransactionFromImageWithProgress_FullMethodName = "/ai.v3.AiWrapperService/BuildTransactionFromImageWithProgress"
+	AiWrapperService_StreamTransaction_FullMethodName                     = "/ai.v3.AiWrapperService/StreamTransaction"
+	AiWrapperService_BuildTransactionsFromText_FullMethodName             = "/ai.v3.AiWrapperService/BuildTransactionsFromText"
+)
+
+// AiWrapperServiceClient is the client API for AiWrapperService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AiWrapperServiceClient interface {
+	BuildTransactionFromText(ctx context.Context, in *BuildTransactionFromTextRequest, opts ...grpc.CallOption) (*TransactionResponseV3, error)
+	BuildTransactionFromImage(ctx context.Context, in *v2.BuildTransactionFromImageRequest, opts ...grpc.CallOption) (*TransactionResponseV3, error)
+	UploadAndBuildTransaction(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRequest, TransactionResponseV3], error)
+	BuildTransactionFromImageWithProgress(ctx context.Context, in *v2.BuildTransactionFromImageRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgressEvent], error)
+	StreamTransaction(ctx context.Context, in *StreamTransactionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionStreamEvent], error)
+	BuildTransactionsFromText(ctx context.Context, in *BuildTransactionFromTextRequest, opts ...grpc.CallOption) (*BuildTransactionsFromTextResponse, error)
+}
+
+type aiWrapperServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAiWrapperServiceClient(cc grpc.ClientConnInterface) AiWrapperServiceClient {
+	return &aiWrapperServiceClient{cc}
+}
+
+func (c *aiWrapperServiceClient) BuildTransactionFromText(ctx context.Context, in *BuildTransactionFromTextRequest, opts ...grpc.CallOption) (*TransactionResponseV3, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponseV3)
+	err := c.cc.Invoke(ctx, AiWrapperService_BuildTransactionFromText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiWrapperServiceClient) BuildTransactionFromImage(ctx context.Context, in *v2.BuildTransactionFromImageRequest, opts ...grpc.CallOption) (*TransactionResponseV3, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponseV3)
+	err := c.cc.Invoke(ctx, AiWrapperService_BuildTransactionFromImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiWrapperServiceClient) UploadAndBuildTransaction(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRequest, TransactionResponseV3], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AiWrapperService_ServiceDesc.Streams[0], AiWrapperService_UploadAndBuildTransaction_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadRequest, TransactionResponseV3]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_UploadAndBuildTransactionClient = grpc.ClientStreamingClient[UploadRequest, TransactionResponseV3]
+
+func (c *aiWrapperServiceClient) BuildTransactionFromImageWithProgress(ctx context.Context, in *v2.BuildTransactionFromImageRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgressEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AiWrapperService_ServiceDesc.Streams[1], AiWrapperService_BuildTransactionFromImageWithProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[v2.BuildTransactionFromImageRequest, ProgressEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_BuildTransactionFromImageWithProgressClient = grpc.ServerStreamingClient[ProgressEvent]
+
+func (c *aiWrapperServiceClient) StreamTransaction(ctx context.Context, in *StreamTransactionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionStreamEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AiWrapperService_ServiceDesc.Streams[2], AiWrapperService_StreamTransaction_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTransactionRequest, TransactionStreamEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_StreamTransactionClient = grpc.ServerStreamingClient[TransactionStreamEvent]
+
+func (c *aiWrapperServiceClient) BuildTransactionsFromText(ctx context.Context, in *BuildTransactionFromTextRequest, opts ...grpc.CallOption) (*BuildTransactionsFromTextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BuildTransactionsFromTextResponse)
+	err := c.cc.Invoke(ctx, AiWrapperService_BuildTransactionsFromText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AiWrapperServiceServer is the server API for AiWrapperService service.
+// All implementations must embed UnimplementedAiWrapperServiceServer
+// for forward compatibility.
+type AiWrapperServiceServer interface {
+	BuildTransactionFromText(context.Context, *BuildTransactionFromTextRequest) (*TransactionResponseV3, error)
+	BuildTransactionFromImage(context.Context, *v2.BuildTransactionFromImageRequest) (*TransactionResponseV3, error)
+	UploadAndBuildTransaction(grpc.ClientStreamingServer[UploadRequest, TransactionResponseV3]) error
+	BuildTransactionFromImageWithProgress(*v2.BuildTransactionFromImageRequest, grpc.ServerStreamingServer[ProgressEvent]) error
+	StreamTransaction(*StreamTransactionRequest, grpc.ServerStreamingServer[TransactionStreamEvent]) error
+	BuildTransactionsFromText(context.Context, *BuildTransactionFromTextRequest) (*BuildTransactionsFromTextResponse, error)
+	mustEmbedUnimplementedAiWrapperServiceServer()
+}
+
+// UnimplementedAiWrapperServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAiWrapperServiceServer struct{}
+
+func (UnimplementedAiWrapperServiceServer) BuildTransactionFromText(context.Context, *BuildTransactionFromTextRequest) (*TransactionResponseV3, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildTransactionFromText not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) BuildTransactionFromImage(context.Context, *v2.BuildTransactionFromImageRequest) (*TransactionResponseV3, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildTransactionFromImage not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) UploadAndBuildTransaction(grpc.ClientStreamingServer[UploadRequest, TransactionResponseV3]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAndBuildTransaction not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) BuildTransactionFromImageWithProgress(*v2.BuildTransactionFromImageRequest, grpc.ServerStreamingServer[ProgressEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method BuildTransactionFromImageWithProgress not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) StreamTransaction(*StreamTransactionRequest, grpc.ServerStreamingServer[TransactionStreamEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTransaction not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) BuildTransactionsFromText(context.Context, *BuildTransactionFromTextRequest) (*BuildTransactionsFromTextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildTransactionsFromText not implemented")
+}
+func (UnimplementedAiWrapperServiceServer) mustEmbedUnimplementedAiWrapperServiceServer() {}
+func (UnimplementedAiWrapperServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAiWrapperServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AiWrapperServiceServer will
+// result in compilation errors.
+type UnsafeAiWrapperServiceServer interface {
+	mustEmbedUnimplementedAiWrapperServiceServer()
+}
+
+func RegisterAiWrapperServiceServer(s grpc.ServiceRegistrar, srv AiWrapperServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAiWrapperServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AiWrapperService_ServiceDesc, srv)
+}
+
+func _AiWrapperService_BuildTransactionFromText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildTransactionFromTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiWrapperServiceServer).BuildTransactionFromText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiWrapperService_BuildTransactionFromText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiWrapperServiceServer).BuildTransactionFromText(ctx, req.(*BuildTransactionFromTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiWrapperService_BuildTransactionFromImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v2.BuildTransactionFromImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiWrapperServiceServer).BuildTransactionFromImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiWrapperService_BuildTransactionFromImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiWrapperServiceServer).BuildTransactionFromImage(ctx, req.(*v2.BuildTransactionFromImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiWrapperService_UploadAndBuildTransaction_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AiWrapperServiceServer).UploadAndBuildTransaction(&grpc.GenericServerStream[UploadRequest, TransactionResponseV3]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_UploadAndBuildTransactionServer = grpc.ClientStreamingServer[UploadRequest, TransactionResponseV3]
+
+func _AiWrapperService_BuildTransactionFromImageWithProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(v2.BuildTransactionFromImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AiWrapperServiceServer).BuildTransactionFromImageWithProgress(m, &grpc.GenericServerStream[v2.BuildTransactionFromImageRequest, ProgressEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_BuildTransactionFromImageWithProgressServer = grpc.ServerStreamingServer[ProgressEvent]
+
+func _AiWrapperService_StreamTransaction_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTransactionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AiWrapperServiceServer).StreamTransaction(m, &grpc.GenericServerStream[StreamTransactionRequest, TransactionStreamEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiWrapperService_StreamTransactionServer = grpc.ServerStreamingServer[TransactionStreamEvent]
+
+func _AiWrapperService_BuildTransactionsFromText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildTransactionFromTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiWrapperServiceServer).BuildTransactionsFromText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiWrapperService_BuildTransactionsFromText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiWrapperServiceServer).BuildTransactionsFromText(ctx, req.(*BuildTransactionFromTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AiWrapperService_ServiceDesc is the grpc.ServiceDesc for AiWrapperService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AiWrapperService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ai.v3.AiWrapperService",
+	HandlerType: (*AiWrapperServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BuildTransactionFromText",
+			Handler:    _AiWrapperService_BuildTransactionFromText_Handler,
+		},
+		{
+			MethodName: "BuildTransactionFromImage",
+			Handler:    _AiWrapperService_BuildTransactionFromImage_Handler,
+		},
+		{
+			MethodName: "BuildTransactionsFromText",
+			Handler:    _AiWrapperService_BuildTransactionsFromText_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAndBuildTransaction",
+			Handler:       _AiWrapperService_UploadAndBuildTransaction_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BuildTransactionFromImageWithProgress",
+			Handler:       _AiWrapperService_BuildTransactionFromImageWithProgress_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTransaction",
+			Handler:       _AiWrapperService_StreamTransaction_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ai/v3/ai.proto",
+}
@@ -0,0 +1,13 @@
+#!/bin/sh
+# Regenerates proto/gen from proto/ai/v3/ai.proto.
+# Needs protoc, protoc-gen-go and protoc-gen-go-grpc on PATH.
+set -e
+cd "$(dirname "$0")"
+
+SHARED=$(go list -m -f '{{.Dir}}' github.com/cp25sy5-modjot/proto)
+V2='Mai/v2/ai.proto=github.com/cp25sy5-modjot/proto/gen/ai/v2;aiwpb'
+
+protoc -I . -I "$SHARED" \
+  --go_out=gen --go_opt=paths=source_relative,$V2 \
+  --go-grpc_out=gen --go-grpc_opt=paths=source_relative,$V2 \
+  ai/v3/ai.proto